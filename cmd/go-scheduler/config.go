@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Duration wraps time.Duration to read it from the schedule file in the
+// human-readable format accepted by time.ParseDuration (e.g. "1m30s").
+type Duration time.Duration
+
+// UnmarshalJSON parses duration from the JSON string.
+func (duration *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration shall be a string, for example \"1m30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*duration = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON formats duration as the JSON string.
+func (duration Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(duration).String())
+}
+
+// Config represents the schedule file.
+type Config struct {
+	// Tasks stores definitions of all scheduled tasks.
+	Tasks []TaskConfig `json:"tasks"`
+}
+
+// TaskConfig represents one task definition from the schedule file. Exactly one
+// of Shell or HTTP shall be set.
+type TaskConfig struct {
+	// Name is used to identify the task in the output, it shall be unique within
+	// the schedule file.
+	Name string `json:"name"`
+	// Start is the first schedule time, if empty the task starts immediately.
+	Start *time.Time `json:"start,omitempty"`
+	// Duration stores how long the task shall be kept by the scheduler, if empty
+	// the task runs until the program is interrupted.
+	Duration *Duration `json:"duration,omitempty"`
+	// Interval stores how often the task shall be triggered.
+	Interval Duration `json:"interval"`
+	// Shell stores command that will be executed using the system shell.
+	Shell *ShellConfig `json:"shell,omitempty"`
+	// HTTP stores request that will be sent on each execution.
+	HTTP *HTTPConfig `json:"http,omitempty"`
+}
+
+// ShellConfig represents shell command job.
+type ShellConfig struct {
+	// Command is passed to the "/bin/sh -c".
+	Command string `json:"command"`
+	// Env stores additional environment variables for the command.
+	Env map[string]string `json:"env,omitempty"`
+	// Dir is a working directory of the command, current directory if empty.
+	Dir string `json:"dir,omitempty"`
+}
+
+// HTTPConfig represents HTTP request job.
+type HTTPConfig struct {
+	// Method is HTTP method, GET if empty.
+	Method string `json:"method,omitempty"`
+	// URL is the request target.
+	URL string `json:"url"`
+	// Headers stores request headers.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body stores request body, it is a text/template executed with
+	// scheduler.HTTPTemplateData.
+	Body string `json:"body,omitempty"`
+	// Timeout limits request duration, no limit if empty.
+	Timeout *Duration `json:"timeout,omitempty"`
+	// ExpectStatus lists accepted response status codes, any 2xx if empty.
+	ExpectStatus []int `json:"expect_status,omitempty"`
+}
+
+// LoadConfig reads and validates schedule file from the provided path.
+func LoadConfig(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	config := &Config{}
+	if err = decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+
+	if err = config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schedule %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Validate checks that all task definitions are correct, it returns all found
+// problems joined together.
+func (config *Config) Validate() error {
+	var problems []error
+
+	if len(config.Tasks) == 0 {
+		problems = append(problems, errors.New("schedule has no tasks"))
+	}
+
+	names := make(map[string]bool)
+	for index, task := range config.Tasks {
+		if err := task.Validate(); err != nil {
+			problems = append(problems, fmt.Errorf("task #%d (%s): %w", index+1, task.Name, err))
+		}
+		if task.Name != "" && names[task.Name] {
+			problems = append(problems, fmt.Errorf("task #%d: duplicated name %q", index+1, task.Name))
+		}
+		names[task.Name] = true
+	}
+
+	return errors.Join(problems...)
+}
+
+// Validate checks that the task definition is correct.
+func (task *TaskConfig) Validate() error {
+	var problems []error
+
+	if strings.TrimSpace(task.Name) == "" {
+		problems = append(problems, errors.New("name is required"))
+	}
+
+	if task.Interval <= 0 {
+		problems = append(problems, errors.New("interval shall be positive"))
+	}
+
+	if task.Duration != nil && *task.Duration <= 0 {
+		problems = append(problems, errors.New("duration shall be positive"))
+	}
+
+	switch {
+	case task.Shell == nil && task.HTTP == nil:
+		problems = append(problems, errors.New("either shell or http job is required"))
+	case task.Shell != nil && task.HTTP != nil:
+		problems = append(problems, errors.New("only one of shell or http job is allowed"))
+	case task.Shell != nil:
+		if strings.TrimSpace(task.Shell.Command) == "" {
+			problems = append(problems, errors.New("shell command is required"))
+		}
+	case task.HTTP != nil:
+		if err := task.HTTP.Validate(); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// Validate checks that the HTTP request definition is correct.
+func (config *HTTPConfig) Validate() error {
+	target, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("url %q shall use http or https scheme", config.URL)
+	}
+	if config.Timeout != nil && *config.Timeout <= 0 {
+		return errors.New("http timeout shall be positive")
+	}
+	for _, status := range config.ExpectStatus {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("invalid expected status %d", status)
+		}
+	}
+	if config.Method != "" && strings.ToUpper(config.Method) != config.Method {
+		return fmt.Errorf("http method %q shall be upper case", config.Method)
+	}
+	if _, err = template.New("body").Parse(config.Body); err != nil {
+		return fmt.Errorf("invalid http body template: %w", err)
+	}
+	return nil
+}
+
+// FindTask returns task definition with provided name or nil if it doesn't
+// exist.
+func (config *Config) FindTask(name string) *TaskConfig {
+	for index := range config.Tasks {
+		if config.Tasks[index].Name == name {
+			return &config.Tasks[index]
+		}
+	}
+	return nil
+}
+
+// NextRuns returns up to count fire times of the task that happen at or after
+// provided moment. It follows the same rules as the scheduler: the first run
+// happens at the start time, then every interval before the end of the
+// duration.
+func (task *TaskConfig) NextRuns(after time.Time, count int) []time.Time {
+	// The definition has no cron expression, so the preview could not fail.
+	runs, _ := task.task().NextRuns(after, count)
+	return runs
+}
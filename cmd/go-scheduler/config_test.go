@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dl1998/go-scheduler/pkg/scheduler"
+)
+
+// WriteConfig writes schedule file with provided content to the temporary
+// directory and returns its path.
+func WriteConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Cannot write schedule file: %v.", err)
+	}
+	return path
+}
+
+// TestLoadConfig tests that LoadConfig reads all supported fields from the
+// schedule file.
+func TestLoadConfig(t *testing.T) {
+	path := WriteConfig(t, `{
+		"tasks": [
+			{"name": "shell", "interval": "1m", "duration": "1h", "shell": {"command": "echo hello", "env": {"A": "B"}}},
+			{"name": "http", "interval": "30s", "http": {"url": "http://localhost/health", "timeout": "5s", "expect_status": [204]}}
+		]
+	}`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Valid schedule file has not been loaded: %v.", err)
+	}
+
+	if len(config.Tasks) != 2 {
+		t.Fatalf("Incorrect number of tasks. Expected: 2. Actual: %d.", len(config.Tasks))
+	}
+
+	shell := config.FindTask("shell")
+	if shell == nil || time.Duration(shell.Interval) != time.Minute || time.Duration(*shell.Duration) != time.Hour || shell.Shell.Env["A"] != "B" {
+		t.Fatalf("Shell task has been parsed incorrectly: %+v.", shell)
+	}
+
+	httpTask := config.FindTask("http")
+	if httpTask == nil || time.Duration(*httpTask.HTTP.Timeout) != 5*time.Second || httpTask.HTTP.ExpectStatus[0] != 204 {
+		t.Fatalf("HTTP task has been parsed incorrectly: %+v.", httpTask)
+	}
+}
+
+// TestConfig_Validate tests that Config.Validate reports every problem of the
+// schedule file.
+func TestConfig_Validate(t *testing.T) {
+	interval := Duration(time.Second)
+	config := &Config{Tasks: []TaskConfig{
+		{Name: "", Interval: interval, Shell: &ShellConfig{Command: "true"}},
+		{Name: "no interval", Shell: &ShellConfig{Command: "true"}},
+		{Name: "no job", Interval: interval},
+		{Name: "bad url", Interval: interval, HTTP: &HTTPConfig{URL: "ftp://localhost"}},
+		{Name: "duplicate", Interval: interval, Shell: &ShellConfig{Command: "true"}},
+		{Name: "duplicate", Interval: interval, Shell: &ShellConfig{Command: "true"}},
+	}}
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatalf("Invalid schedule has passed validation.")
+	}
+
+	for _, expected := range []string{"name is required", "interval shall be positive", "either shell or http", "http or https", "duplicated name"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Validation error does not contain %q. Actual: %v.", expected, err)
+		}
+	}
+}
+
+// TestTaskConfig_NextRuns tests that TaskConfig.NextRuns returns fire times
+// aligned to the start time and limited by the duration, the end of the
+// duration is excluded.
+func TestTaskConfig_NextRuns(t *testing.T) {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	duration := Duration(3 * time.Hour)
+	task := &TaskConfig{Name: "task", Start: &start, Duration: &duration, Interval: Duration(time.Hour)}
+
+	runs := task.NextRuns(start.Add(90*time.Minute), 5)
+	expected := []time.Time{start.Add(2 * time.Hour)}
+
+	if len(runs) != len(expected) {
+		t.Fatalf("Incorrect number of runs. Expected: %v. Actual: %v.", expected, runs)
+	}
+	for index := range expected {
+		if !runs[index].Equal(expected[index]) {
+			t.Fatalf("Incorrect run time. Expected: %v. Actual: %v.", expected, runs)
+		}
+	}
+}
+
+// TestTaskConfig_NextRuns_End tests that TaskConfig.NextRuns previews the same
+// fire times as the scheduler executes before the end of the duration.
+func TestTaskConfig_NextRuns_End(t *testing.T) {
+	start := time.Now().Add(50 * time.Millisecond)
+	duration := Duration(300 * time.Millisecond)
+	config := &TaskConfig{Name: "task", Start: &start, Duration: &duration, Interval: Duration(100 * time.Millisecond)}
+
+	preview := config.NextRuns(start, 10)
+
+	newScheduler := scheduler.New()
+	defer newScheduler.Shutdown(context.Background())
+	task := config.task()
+	_ = newScheduler.Schedule(task, func(task *scheduler.Task) {})
+	task.Wait()
+
+	history := task.History()
+	if len(preview) != 3 || len(history) != len(preview) {
+		t.Fatalf("Preview doesn't match executed runs. Preview: %v. Executed: %d.", preview, len(history))
+	}
+	for index, run := range history {
+		if !run.Scheduled.Equal(preview[index]) {
+			t.Fatalf("Incorrect previewed run time. Expected: %v. Actual: %v.", run.Scheduled, preview[index])
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"github.com/dl1998/go-scheduler/pkg/scheduler"
+	"net/http"
+	"time"
+)
+
+// task converts task definition to the scheduler Task.
+func (config *TaskConfig) task() *scheduler.Task {
+	var duration *time.Duration
+	if config.Duration != nil {
+		value := time.Duration(*config.Duration)
+		duration = &value
+	}
+	return scheduler.NewTask("", config.Name, config.Start, duration, time.Duration(config.Interval), nil, nil)
+}
+
+// command converts shell job definition to the scheduler Command that runs it
+// using "/bin/sh -c".
+func (config *ShellConfig) command() *scheduler.Command {
+	command := scheduler.NewCommand("/bin/sh", "-c", config.Command)
+	command.Dir = config.Dir
+	for key, value := range config.Env {
+		command.Env = append(command.Env, fmt.Sprintf("%s=%s", key, value))
+	}
+	return command
+}
+
+// request converts HTTP job definition to the scheduler HTTPRequest.
+func (config *HTTPConfig) request() *scheduler.HTTPRequest {
+	request := scheduler.NewHTTPRequest(config.Method, config.URL)
+	request.Body = config.Body
+	request.ExpectedStatus = config.ExpectStatus
+	if config.Timeout != nil {
+		request.Timeout = time.Duration(*config.Timeout)
+	}
+	if len(config.Headers) > 0 {
+		request.Header = make(http.Header)
+		for key, value := range config.Headers {
+			request.Header.Set(key, value)
+		}
+	}
+	return request
+}
@@ -0,0 +1,277 @@
+// Command go-scheduler runs shell commands and HTTP requests periodically
+// according to the schedule file.
+//
+// Usage:
+//
+//	go-scheduler run [-drain-timeout 30s] schedule.json
+//	go-scheduler validate schedule.json
+//	go-scheduler next-runs [-n 5] [-task name] schedule.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/dl1998/go-scheduler/pkg/scheduler"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const usage = `Usage: go-scheduler <command> [flags] <schedule file>
+
+Commands:
+  run        runs tasks from the schedule file until they complete or the program is interrupted
+  validate   checks the schedule file and exits
+  next-runs  prints the next fire times of the tasks from the schedule file
+
+Run "go-scheduler <command> -h" for the command flags.
+`
+
+func main() {
+	os.Exit(execute(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// execute dispatches subcommand and returns the program exit code.
+func execute(arguments []string, stdout io.Writer, stderr io.Writer) int {
+	if len(arguments) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch arguments[0] {
+	case "run":
+		return runCommand(arguments[1:], stdout, stderr)
+	case "validate":
+		return validateCommand(arguments[1:], stdout, stderr)
+	case "next-runs":
+		return nextRunsCommand(arguments[1:], stdout, stderr)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", arguments[0], usage)
+		return 2
+	}
+}
+
+// parseFlags parses subcommand flags and returns path to the schedule file.
+func parseFlags(flags *flag.FlagSet, arguments []string) (string, error) {
+	if err := flags.Parse(arguments); err != nil {
+		return "", err
+	}
+	if flags.NArg() != 1 {
+		return "", fmt.Errorf("%s expects exactly one schedule file", flags.Name())
+	}
+	return flags.Arg(0), nil
+}
+
+// validateCommand loads schedule file and reports whether it is valid.
+func validateCommand(arguments []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	path, err := parseFlags(flags, arguments)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "%s: %d task(s) OK\n", path, len(config.Tasks))
+	return 0
+}
+
+// nextRunsCommand prints the next fire times for all tasks or for the selected
+// one.
+func nextRunsCommand(arguments []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("next-runs", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	count := flags.Int("n", 5, "number of fire times to print per task")
+	taskName := flags.String("task", "", "print fire times only for the task with this name")
+	path, err := parseFlags(flags, arguments)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if *count < 1 {
+		fmt.Fprintf(stderr, "number of fire times shall be positive, got %d\n", *count)
+		flags.Usage()
+		return 2
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	tasks := config.Tasks
+	if *taskName != "" {
+		task := config.FindTask(*taskName)
+		if task == nil {
+			fmt.Fprintf(stderr, "task %q not found in %s\n", *taskName, path)
+			return 1
+		}
+		tasks = []TaskConfig{*task}
+	}
+
+	now := time.Now()
+	for _, task := range tasks {
+		fmt.Fprintf(stdout, "%s (%s):\n", task.Name, task.task().Describe())
+		runs := task.NextRuns(now, *count)
+		if len(runs) == 0 {
+			fmt.Fprintln(stdout, "  no upcoming runs")
+		}
+		for _, fireTime := range runs {
+			fmt.Fprintf(stdout, "  %s\n", fireTime.Format(time.RFC3339))
+		}
+	}
+	return 0
+}
+
+// runCommand schedules all tasks from the schedule file and waits until they
+// are completed or the program receives SIGINT/SIGTERM. On signal it stops
+// scheduling new runs and waits for the running ones up to the drain timeout,
+// the second signal or the drain timeout cancels them.
+func runCommand(arguments []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	drainTimeout := flags.Duration("drain-timeout", 30*time.Second, "how long to wait for running jobs after interruption")
+	path, err := parseFlags(flags, arguments)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	runner := &runner{output: stdout}
+	newScheduler := scheduler.New()
+	tasks := make([]*scheduler.Task, 0, len(config.Tasks))
+	for index := range config.Tasks {
+		task := config.Tasks[index].task()
+		if err = newScheduler.Schedule(task, runner.execute, &config.Tasks[index]); err != nil {
+			fmt.Fprintln(stderr, err)
+			_ = newScheduler.Shutdown(context.Background())
+			return 1
+		}
+		runner.printf("scheduled task %q (id: %s, %s)\n", task.Name, task.ID, task.Describe())
+		tasks = append(tasks, task)
+	}
+
+	completed := make(chan struct{})
+	go func() {
+		for _, task := range tasks {
+			task.Wait()
+		}
+		close(completed)
+	}()
+
+	select {
+	case <-completed:
+		_ = newScheduler.Shutdown(context.Background())
+		runner.printf("all tasks completed\n")
+		return 0
+	case received := <-signals:
+		runner.printf("received %s, waiting up to %s for running jobs\n", received, *drainTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *drainTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case received := <-signals:
+			runner.printf("received %s, cancelling running jobs\n", received)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err = newScheduler.Shutdown(ctx); err != nil {
+		runner.printf("running jobs have been cancelled: %v\n", err)
+		return 1
+	}
+	runner.printf("all running jobs completed\n")
+	return 0
+}
+
+// runner executes jobs for the scheduled tasks and prints their status.
+type runner struct {
+	// mutex serializes writes to the output.
+	mutex  sync.Mutex
+	output io.Writer
+}
+
+// printf writes a timestamped status line to the output.
+func (runner *runner) printf(format string, arguments ...interface{}) {
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+	fmt.Fprintf(runner.output, "%s "+format, append([]interface{}{time.Now().Format(time.RFC3339)}, arguments...)...)
+}
+
+// execute is scheduled for every task, it runs the configured job and prints
+// its status.
+func (runner *runner) execute(ctx context.Context, task *scheduler.Task, config *TaskConfig) (interface{}, error) {
+	run := scheduler.RunFromContext(ctx)
+	runner.printf("task %q run #%d started\n", task.Name, run.Number)
+
+	var result interface{}
+	var err error
+	switch {
+	case config.Shell != nil:
+		result, err = config.Shell.command().Run(ctx, task)
+	case config.HTTP != nil:
+		result, err = config.HTTP.request().Run(ctx, task)
+	default:
+		err = errors.New("task has no job")
+	}
+
+	elapsed := time.Since(run.Started).Round(time.Millisecond)
+	if err != nil {
+		runner.printf("task %q run #%d failed after %s: %v\n", task.Name, run.Number, elapsed, err)
+	} else {
+		runner.printf("task %q run #%d succeeded in %s\n", task.Name, run.Number, elapsed)
+	}
+	switch output := result.(type) {
+	case *scheduler.CommandResult:
+		runner.printOutput(output.Stdout)
+		runner.printOutput(output.Stderr)
+	case *scheduler.HTTPResult:
+		if output != nil {
+			runner.printOutput(fmt.Sprintf("HTTP %d", output.StatusCode))
+		}
+	}
+	return result, err
+}
+
+// printOutput writes captured output of the job indented under its status
+// line.
+func (runner *runner) printOutput(output string) {
+	output = strings.TrimRight(output, "\n")
+	if output == "" {
+		return
+	}
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+	for _, line := range strings.Split(output, "\n") {
+		fmt.Fprintf(runner.output, "    %s\n", line)
+	}
+}
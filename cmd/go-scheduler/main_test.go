@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestExecute_Validate tests that validate subcommand returns non-zero exit code
+// for the invalid schedule file.
+func TestExecute_Validate(t *testing.T) {
+	valid := WriteConfig(t, `{"tasks": [{"name": "task", "interval": "1s", "shell": {"command": "true"}}]}`)
+	invalid := WriteConfig(t, `{"tasks": [{"name": "task", "interval": "1s"}]}`)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if code := execute([]string{"validate", valid}, stdout, stderr); code != 0 {
+		t.Fatalf("Valid schedule has been rejected with code %d: %s.", code, stderr.String())
+	}
+
+	if code := execute([]string{"validate", invalid}, stdout, stderr); code != 1 {
+		t.Fatalf("Invalid schedule has been accepted with code %d.", code)
+	}
+}
+
+// TestExecute_Run tests that run subcommand executes tasks until they are
+// completed.
+func TestExecute_Run(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.txt")
+	path := WriteConfig(t, `{"tasks": [{"name": "task", "interval": "100ms", "duration": "250ms", "shell": {"command": "echo run >> `+output+`"}}]}`)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if code := execute([]string{"run", path}, stdout, stderr); code != 0 {
+		t.Fatalf("Run has failed with code %d: %s.", code, stderr.String())
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Task has not been executed: %v.", err)
+	}
+	if runs := strings.Count(string(content), "run"); runs != 3 {
+		t.Fatalf("Incorrect number of runs. Expected: 3. Actual: %d. Output: %s.", runs, stdout.String())
+	}
+}
+
+// TestExecute_NextRuns tests that next-runs subcommand prints fire times and
+// rejects the number of fire times that is not positive.
+func TestExecute_NextRuns(t *testing.T) {
+	path := WriteConfig(t, `{"tasks": [{"name": "task", "interval": "1m", "shell": {"command": "true"}}]}`)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if code := execute([]string{"next-runs", "-n", "2", path}, stdout, stderr); code != 0 || strings.Count(stdout.String(), "\n  ") != 2 {
+		t.Fatalf("Fire times have not been printed, code %d: %s%s.", code, stdout.String(), stderr.String())
+	}
+
+	for _, count := range []string{"0", "-1"} {
+		stderr.Reset()
+		if code := execute([]string{"next-runs", "-n", count, path}, stdout, stderr); code != 2 || !strings.Contains(stderr.String(), "shall be positive") {
+			t.Fatalf("Number of fire times %s has been accepted with code %d: %s.", count, code, stderr.String())
+		}
+	}
+}
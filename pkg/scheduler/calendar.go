@@ -0,0 +1,278 @@
+package scheduler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// calendarHorizon limits how far the next fire time allowed by the calendar is
+// searched for.
+const calendarHorizon = 5 * 366 * 24 * time.Hour
+
+// Calendar defines days when the task could be fired, days are evaluated in
+// the location of the fire time.
+type Calendar interface {
+	// Contains checks whether the task could be fired at provided time.
+	Contains(moment time.Time) bool
+}
+
+// CalendarFunc is a function that implements Calendar.
+type CalendarFunc func(moment time.Time) bool
+
+// Contains calls the function.
+func (function CalendarFunc) Contains(moment time.Time) bool {
+	return function(moment)
+}
+
+// CalendarPolicy defines what happens with the fire time that is not contained
+// in the Calendar of the task.
+type CalendarPolicy int
+
+const (
+	// CalendarSkip skips the fire time, it is the default policy.
+	CalendarSkip CalendarPolicy = iota
+	// CalendarShift moves the fire time to the next day contained in the
+	// calendar at the same time of day, e.g. to the next business day. Several
+	// fire times moved to the same time are fired once.
+	CalendarShift
+)
+
+// WeeklyCalendar contains selected days of the week.
+type WeeklyCalendar struct {
+	days [7]bool
+}
+
+// NewWeeklyCalendar creates a calendar that contains provided days of the
+// week.
+func NewWeeklyCalendar(days ...time.Weekday) *WeeklyCalendar {
+	calendar := &WeeklyCalendar{}
+	for _, day := range days {
+		calendar.days[day%7] = true
+	}
+	return calendar
+}
+
+// BusinessDays contains days from Monday to Friday.
+var BusinessDays = NewWeeklyCalendar(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+
+// Contains checks whether the day of the week is selected.
+func (calendar *WeeklyCalendar) Contains(moment time.Time) bool {
+	return calendar.days[moment.Weekday()]
+}
+
+// date is the calendar date without time and location.
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// dateOf returns the date of the time in its location.
+func dateOf(moment time.Time) date {
+	year, month, day := moment.Date()
+	return date{year: year, month: month, day: day}
+}
+
+// DateCalendar contains explicitly listed dates, e.g. public holidays. Dates
+// are compared in the location of the checked time.
+type DateCalendar struct {
+	dates map[date]struct{}
+}
+
+// NewDateCalendar creates a calendar that contains dates of provided times.
+func NewDateCalendar(dates ...time.Time) *DateCalendar {
+	calendar := &DateCalendar{dates: make(map[date]struct{})}
+	for _, moment := range dates {
+		calendar.Add(moment)
+	}
+	return calendar
+}
+
+// Add adds the date of provided time to the calendar.
+func (calendar *DateCalendar) Add(moment time.Time) {
+	calendar.dates[dateOf(moment)] = struct{}{}
+}
+
+// Dates returns dates of the calendar in ascending order as midnight UTC.
+func (calendar *DateCalendar) Dates() []time.Time {
+	dates := make([]time.Time, 0, len(calendar.dates))
+	for value := range calendar.dates {
+		dates = append(dates, time.Date(value.year, value.month, value.day, 0, 0, 0, 0, time.UTC))
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+	return dates
+}
+
+// Contains checks whether the date is listed.
+func (calendar *DateCalendar) Contains(moment time.Time) bool {
+	_, ok := calendar.dates[dateOf(moment)]
+	return ok
+}
+
+// ParseICalendar reads dates of all events from the iCalendar (RFC 5545) data,
+// e.g. exported public holidays. Every day from DTSTART until DTEND (exclusive)
+// is added, recurrence rules are not supported.
+func ParseICalendar(reader io.Reader) (*DateCalendar, error) {
+	calendar := NewDateCalendar()
+	lines, err := unfoldICalendar(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var start, end time.Time
+	inEvent := false
+	for number, line := range lines {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, start, end = true, time.Time{}, time.Time{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if start.IsZero() {
+				return nil, fmt.Errorf("invalid iCalendar: event without DTSTART ends at line %d", number+1)
+			}
+			calendar.Add(start)
+			for day := start.AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+				calendar.Add(day)
+			}
+			inEvent = false
+		case inEvent && (name == "DTSTART" || name == "DTEND"):
+			parsed, err := parseICalendarDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid iCalendar: line %d: %w", number+1, err)
+			}
+			if name == "DTSTART" {
+				start = parsed
+			} else {
+				end = parsed
+			}
+		}
+	}
+	return calendar, nil
+}
+
+// unfoldICalendar reads content lines, joining folded lines.
+func unfoldICalendar(reader io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseICalendarDate parses the date part of the DATE or DATE-TIME value.
+func parseICalendarDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	parsed, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return parsed, nil
+}
+
+// combinedCalendar is the calendar combined from other calendars, it is
+// described using their descriptions joined by the conjunction.
+type combinedCalendar struct {
+	contains    func(moment time.Time) bool
+	calendars   []Calendar
+	conjunction string
+}
+
+// Contains checks whether the combined calendars contain the time.
+func (calendar *combinedCalendar) Contains(moment time.Time) bool {
+	return calendar.contains(moment)
+}
+
+// String describes the combined calendars, e.g. "weekdays except 2024-12-25".
+func (calendar *combinedCalendar) String() string {
+	descriptions := make([]string, 0, len(calendar.calendars))
+	for _, combined := range calendar.calendars {
+		descriptions = append(descriptions, describeCalendar(combined))
+	}
+	if calendar.conjunction == "except" {
+		return strings.Join(descriptions, " except ")
+	}
+	return joinWords(descriptions, calendar.conjunction)
+}
+
+// Union returns the calendar that contains days contained in any of the
+// calendars.
+func Union(calendars ...Calendar) Calendar {
+	return &combinedCalendar{calendars: calendars, conjunction: "or", contains: func(moment time.Time) bool {
+		for _, calendar := range calendars {
+			if calendar.Contains(moment) {
+				return true
+			}
+		}
+		return false
+	}}
+}
+
+// Intersection returns the calendar that contains days contained in all
+// calendars.
+func Intersection(calendars ...Calendar) Calendar {
+	return &combinedCalendar{calendars: calendars, conjunction: "and", contains: func(moment time.Time) bool {
+		for _, calendar := range calendars {
+			if !calendar.Contains(moment) {
+				return false
+			}
+		}
+		return true
+	}}
+}
+
+// Except returns the calendar that contains days of the calendar that are not
+// contained in the excluded calendar, e.g. business days except holidays.
+func Except(calendar Calendar, excluded Calendar) Calendar {
+	return &combinedCalendar{calendars: []Calendar{calendar, excluded}, conjunction: "except", contains: func(moment time.Time) bool {
+		return calendar.Contains(moment) && !excluded.Contains(moment)
+	}}
+}
+
+// admits checks whether the candidate fire time is allowed by the calendar of
+// the task, the previous fire time is used to fire the shifted times once.
+func (task *Task) admits(candidate time.Time, previous time.Time) bool {
+	if task.Calendar == nil {
+		return true
+	}
+	if task.CalendarPolicy != CalendarShift {
+		return task.Calendar.Contains(candidate)
+	}
+	shifted := task.calendarTime(candidate)
+	return !shifted.IsZero() && (previous.IsZero() || shifted.After(task.calendarTime(previous)))
+}
+
+// calendarTime returns the time when the planned run is fired according to the
+// calendar of the task, it is moved to the next day contained in the calendar
+// if the task uses CalendarShift. It returns zero time if there is no such day.
+func (task *Task) calendarTime(planned time.Time) time.Time {
+	if planned.IsZero() || task.Calendar == nil || task.CalendarPolicy != CalendarShift {
+		return planned
+	}
+	year, month, day := planned.Date()
+	hour, minute, second := planned.Clock()
+	for offset := 0; offset <= int(calendarHorizon/(24*time.Hour)); offset++ {
+		shifted := localTime(time.Date(year, month, day+offset, hour, minute, second, planned.Nanosecond(), time.UTC), planned.Location())
+		if task.Calendar.Contains(shifted) {
+			return shifted
+		}
+	}
+	return time.Time{}
+}
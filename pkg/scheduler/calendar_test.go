@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCalendars tests weekly and date calendars and their combinations.
+func TestCalendars(t *testing.T) {
+	friday := time.Date(2024, time.December, 20, 9, 0, 0, 0, time.UTC)
+	saturday := friday.AddDate(0, 0, 1)
+	christmas := time.Date(2024, time.December, 25, 9, 0, 0, 0, time.UTC)
+	holidays := NewDateCalendar(christmas)
+	weekend := NewWeeklyCalendar(time.Saturday, time.Sunday)
+	workingDays := Except(BusinessDays, holidays)
+
+	tests := []struct {
+		name     string
+		calendar Calendar
+		moment   time.Time
+		expected bool
+	}{
+		{"Business day", BusinessDays, friday, true},
+		{"Weekend", BusinessDays, saturday, false},
+		{"Holiday", holidays, christmas.Add(12 * time.Hour), true},
+		{"Not holiday", holidays, friday, false},
+		{"Working day", workingDays, friday, true},
+		{"Working day holiday", workingDays, christmas, false},
+		{"Union", Union(weekend, holidays), christmas, true},
+		{"Union other day", Union(weekend, holidays), friday, false},
+		{"Intersection", Intersection(BusinessDays, holidays), christmas, true},
+		{"Intersection other day", Intersection(weekend, holidays), christmas, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.calendar.Contains(test.moment); actual != test.expected {
+				t.Fatalf("Calendar contains %s: %t, expected: %t.", test.moment, actual, test.expected)
+			}
+		})
+	}
+}
+
+// TestParseICalendar tests that dates of all events are imported, including
+// folded lines and events lasting several days.
+func TestParseICalendar(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20241225",
+		"DTEND;VALUE=DATE:20241227",
+		"SUMMARY:Christmas",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20250101T000000Z",
+		"SUMMARY:New Year's",
+		"  Day",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	calendar, err := ParseICalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Calendar has not been parsed. Error: %v.", err)
+	}
+	dates := calendar.Dates()
+	expected := []time.Time{
+		time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.December, 26, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if len(dates) != len(expected) {
+		t.Fatalf("Incorrect dates: %v.", dates)
+	}
+	for index := range expected {
+		if !dates[index].Equal(expected[index]) {
+			t.Fatalf("Incorrect dates: %v.", dates)
+		}
+	}
+
+	if _, err = ParseICalendar(strings.NewReader("BEGIN:VEVENT\nDTSTART:2024\nEND:VEVENT")); err == nil {
+		t.Fatalf("Invalid date has been parsed.")
+	}
+}
+
+// TestTask_Calendar tests that fire times are skipped or shifted to the next
+// day contained in the calendar for intervals and cron expressions.
+func TestTask_Calendar(t *testing.T) {
+	friday := time.Date(2024, time.December, 20, 9, 0, 0, 0, time.UTC)
+	monday := friday.AddDate(0, 0, 3)
+	tuesday := friday.AddDate(0, 0, 4)
+	holidays := NewDateCalendar(time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC))
+
+	task := &Task{Interval: 24 * time.Hour, Calendar: BusinessDays}
+	if next := task.nextPlanned(friday, time.Time{}); !next.Equal(monday) {
+		t.Fatalf("Weekend has not been skipped: %s.", next)
+	}
+	saturday := friday.AddDate(0, 0, 1)
+	if first := task.firstFireTime(saturday); !first.Equal(monday) {
+		t.Fatalf("Incorrect first fire time on the weekend: %s.", first)
+	}
+
+	task = &Task{Interval: time.Minute, Calendar: BusinessDays}
+	if next := task.nextPlanned(friday.Add(15*time.Hour-time.Minute), time.Time{}); !next.Equal(monday.Add(-9 * time.Hour)) {
+		t.Fatalf("Weekend has not been skipped for the short interval: %s.", next)
+	}
+
+	task = &Task{Interval: 24 * time.Hour, Calendar: BusinessDays, CalendarPolicy: CalendarShift}
+	next := task.nextPlanned(friday, time.Time{})
+	if !next.Equal(saturday) || !task.calendarTime(next).Equal(monday) {
+		t.Fatalf("Saturday has not been shifted to Monday: %s.", next)
+	}
+	if next = task.nextPlanned(next, time.Time{}); !next.Equal(tuesday) {
+		t.Fatalf("Fire times shifted to Monday have not been fired once: %s.", next)
+	}
+
+	task = &Task{Cron: "0 9 * * *", Calendar: Except(BusinessDays, holidays)}
+	_ = task.parseCron()
+	christmasEve := time.Date(2024, time.December, 24, 9, 0, 0, 0, time.UTC)
+	if next = task.nextPlanned(christmasEve, time.Time{}); !next.Equal(christmasEve.AddDate(0, 0, 2)) {
+		t.Fatalf("Holiday has not been skipped: %s.", next)
+	}
+	if missed := task.missed(friday, monday.AddDate(0, 0, 1)); missed != 1 {
+		t.Fatalf("Incorrect missed count with the calendar: %d.", missed)
+	}
+
+	task = &Task{Interval: time.Hour, Calendar: NewDateCalendar()}
+	if first := task.firstFireTime(friday); !first.IsZero() {
+		t.Fatalf("Task with the empty calendar has been planned: %s.", first)
+	}
+}
+
+// TestScheduler_Calendar tests that the scheduled task is not fired on days
+// excluded by the calendar and UpdateCalendar changes the calendar.
+func TestScheduler_Calendar(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	today := time.Now()
+	task := NewTask("", "Task", nil, nil, time.Millisecond, nil, nil)
+	task.Calendar = Except(CalendarFunc(func(moment time.Time) bool { return true }), NewDateCalendar(today))
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+
+	newScheduler.mutex.Lock()
+	nextFire := task.nextFire
+	newScheduler.mutex.Unlock()
+	year, month, day := today.Date()
+	if tomorrow := time.Date(year, month, day+1, 0, 0, 0, 0, today.Location()); nextFire.Before(tomorrow) {
+		t.Fatalf("Task has been planned on the excluded day: %s.", nextFire)
+	}
+
+	_ = newScheduler.UpdateTask(task, UpdateCalendar(nil, CalendarSkip))
+	time.Sleep(20 * time.Millisecond)
+	if len(task.History()) == 0 {
+		t.Fatalf("Task has not been fired after the calendar has been removed.")
+	}
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+)
+
+// chainedRunContextKey is the context key under which the Run of the
+// preceding task is passed to the chained task.
+type chainedRunContextKey struct{}
+
+// ChainCondition defines after which runs of the preceding task the chained
+// task is executed.
+type ChainCondition int
+
+const (
+	// OnSuccess executes chained task after every successful run.
+	OnSuccess ChainCondition = iota
+	// OnFailure executes chained task after every failed run.
+	OnFailure
+	// Always executes chained task after every run.
+	Always
+)
+
+// chainLink stores chained task together with its function.
+type chainLink struct {
+	// condition defines when the task is executed.
+	condition ChainCondition
+	// task is the chained task.
+	task *Task
+	// function is called with the chained task and parameters.
+	function interface{}
+	// parameters are passed to the function after the chained task.
+	parameters []interface{}
+}
+
+// matches checks whether chained task shall be executed after the run with
+// provided status.
+func (condition ChainCondition) matches(status RunStatus) bool {
+	switch condition {
+	case OnSuccess:
+		return status == RunSucceeded
+	case OnFailure:
+		return status == RunFailed
+	default:
+		return true
+	}
+}
+
+// Chain adds a follow-up task that is executed right after the run of this task
+// that matches the condition, in the same way as Scheduler.ScheduleTask executes
+// functions. The finished Run of this task, including its Result and Err, is
+// passed in the context.Context of the execution and is returned by
+// ChainedRunFromContext, so the function could process the output:
+//
+//	report := scheduler.NewSimpleTask("Report", 0)
+//	_ = exportTask.Chain(scheduler.OnSuccess, report, func(ctx context.Context, task *scheduler.Task) error {
+//		run, _ := scheduler.ChainedRunFromContext(ctx)
+//		return send(run.Result.(*scheduler.CommandResult).Stdout)
+//	})
+//
+// Chained task could have its own chains, which builds a pipeline. It returns
+// an error if the next task already precedes this task in the chain.
+func (task *Task) Chain(condition ChainCondition, next *Task, function interface{}, parameters ...interface{}) error {
+	if next == task || next.precedes(task) {
+		return fmt.Errorf("task with id: %s cannot be chained, because it precedes task with id: %s", next.ID, task.ID)
+	}
+
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	task.chains = append(task.chains, chainLink{condition: condition, task: next, function: function, parameters: parameters})
+	return nil
+}
+
+// precedes checks whether provided task is directly or transitively chained
+// after this task.
+func (task *Task) precedes(target *Task) bool {
+	task.mutex.RLock()
+	chains := append([]chainLink(nil), task.chains...)
+	task.mutex.RUnlock()
+
+	for _, link := range chains {
+		if link.task == target || link.task.precedes(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChainedRunFromContext returns the Run of the preceding task from the context
+// passed to the function of the chained task. The second value is false if the
+// execution has not been started by the chain. Every execution gets its own
+// Run, so overlapping runs of the preceding task don't affect each other.
+func ChainedRunFromContext(ctx context.Context) (Run, bool) {
+	run, ok := ctx.Value(chainedRunContextKey{}).(Run)
+	return run, ok
+}
+
+// runChains executes chained tasks that match the status of the finished run.
+// Chained runs go through the middlewares, tracing, events, logging and
+// metrics of the scheduler that executed the preceding run, if any. Chained
+// tasks are not executed if the context has been cancelled.
+func (task *Task) runChains(ctx context.Context, run *Run, scheduler *Scheduler) {
+	task.mutex.RLock()
+	chains := append([]chainLink(nil), task.chains...)
+	finishedRun := *run
+	task.mutex.RUnlock()
+
+	for _, link := range chains {
+		if ctx.Err() != nil {
+			return
+		}
+		if !link.condition.matches(finishedRun.Status) {
+			continue
+		}
+		chainedCtx := context.WithValue(ctx, chainedRunContextKey{}, finishedRun)
+		_ = link.task.executeScheduled(chainedCtx, runPlan{scheduler: scheduler}, link.function, link.parameters...)
+	}
+}
@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestTask_Chain tests that Task.Chain executes follow-up tasks according to
+// their conditions and passes the run of the preceding task to them.
+func TestTask_Chain(t *testing.T) {
+	source := NewSimpleTask("Source", time.Second)
+	onSuccess := NewSimpleTask("On Success", 0)
+	onFailure := NewSimpleTask("On Failure", 0)
+	always := NewSimpleTask("Always", 0)
+
+	var received []int
+	var handler = func(ctx context.Context, task *Task) int {
+		run, ok := ChainedRunFromContext(ctx)
+		if !ok {
+			t.Fatalf("Chained run has not been passed to the task %s.", task.Name)
+		}
+		value, _ := run.Result.(int)
+		received = append(received, value)
+		return value * 10
+	}
+
+	_ = source.Chain(OnSuccess, onSuccess, handler)
+	_ = source.Chain(OnFailure, onFailure, handler)
+	_ = source.Chain(Always, always, handler)
+
+	var sourceFunction = func(task *Task, value int, failure bool) (int, error) {
+		if failure {
+			return value, errors.New("failure")
+		}
+		return value, nil
+	}
+
+	_ = source.execute(context.Background(), sourceFunction, 1, false)
+	_ = source.execute(context.Background(), sourceFunction, 2, true)
+
+	if len(onSuccess.History()) != 1 || len(onFailure.History()) != 1 || len(always.History()) != 2 {
+		t.Fatalf("Chained tasks have been executed incorrect number of times: %d, %d, %d.", len(onSuccess.History()), len(onFailure.History()), len(always.History()))
+	}
+
+	if run, _ := onFailure.LastRun(); run.Result != 20 {
+		t.Fatalf("Incorrect result of the chained task. Expected: 20. Actual: %v.", run.Result)
+	}
+
+	if value := onFailure.GetFromContext("chain.run"); value != nil {
+		t.Fatalf("Chained run has been stored in the task context: %+v.", value)
+	}
+
+	if len(received) != 4 {
+		t.Fatalf("Incorrect number of chained executions: %v.", received)
+	}
+}
+
+// TestTask_Chain_Pipeline tests that chained tasks could have their own chains.
+func TestTask_Chain_Pipeline(t *testing.T) {
+	first := NewSimpleTask("First", time.Second)
+	second := NewSimpleTask("Second", 0)
+	third := NewSimpleTask("Third", 0)
+
+	var increment = func(ctx context.Context, task *Task) int {
+		run, _ := ChainedRunFromContext(ctx)
+		return run.Result.(int) + 1
+	}
+
+	_ = first.Chain(OnSuccess, second, increment)
+	_ = second.Chain(OnSuccess, third, increment)
+
+	_ = first.execute(context.Background(), func(task *Task) int { return 1 })
+
+	if run, _ := third.LastRun(); run.Result != 3 {
+		t.Fatalf("Incorrect pipeline result. Expected: 3. Actual: %v.", run.Result)
+	}
+}
+
+// TestTask_Chain_Cycle tests that Task.Chain rejects the task that precedes
+// the current one.
+func TestTask_Chain_Cycle(t *testing.T) {
+	first := NewSimpleTask("First", time.Second)
+	second := NewSimpleTask("Second", 0)
+
+	if err := first.Chain(Always, second, func(task *Task) {}); err != nil {
+		t.Fatalf("Task has not been chained: %v.", err)
+	}
+
+	if err := second.Chain(Always, first, func(task *Task) {}); err == nil {
+		t.Fatalf("Cycle in the chain has not been detected.")
+	}
+
+	if err := first.Chain(Always, first, func(task *Task) {}); err == nil {
+		t.Fatalf("Task has been chained to itself.")
+	}
+}
+
+// TestTask_Chain_Overlap tests that overlapping runs of the preceding task pass
+// their own runs to the chained task, including the zero value task.
+func TestTask_Chain_Overlap(t *testing.T) {
+	source := NewSimpleTask("Source", time.Second)
+	next := &Task{}
+
+	var mutex sync.Mutex
+	received := make(map[int]bool)
+	_ = source.Chain(OnSuccess, next, func(ctx context.Context, task *Task) {
+		run, _ := ChainedRunFromContext(ctx)
+		mutex.Lock()
+		received[run.Result.(int)] = true
+		mutex.Unlock()
+	})
+
+	var group sync.WaitGroup
+	for value := 0; value < 10; value++ {
+		group.Add(1)
+		go func(value int) {
+			defer group.Done()
+			_ = source.execute(context.Background(), func(task *Task) int { return value })
+		}(value)
+	}
+	group.Wait()
+
+	if len(received) != 10 {
+		t.Fatalf("Chained task has not received every run: %v.", received)
+	}
+	if _, ok := ChainedRunFromContext(context.Background()); ok {
+		t.Fatalf("Chained run has been returned outside of the chain.")
+	}
+}
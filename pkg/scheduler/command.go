@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// DefaultMaxOutput is the number of bytes captured from each of stdout and
+// stderr of the Command, if other limit was not set.
+const DefaultMaxOutput = 64 * 1024
+
+// Command is a job that runs external program. Its Run method could be passed
+// directly to the Scheduler.ScheduleTask:
+//
+//	command := scheduler.NewCommand("/usr/bin/backup", "--incremental")
+//	newScheduler.ScheduleTask("Backup", nil, nil, time.Hour, command.Run)
+//
+// When the task is stopped, the whole process group of the running program is
+// killed. Output and exit code of the program are stored as CommandResult in
+// the Run record, non-zero exit code marks execution as failed.
+type Command struct {
+	// Path is the name or path of the program, it is resolved using PATH if it
+	// contains no path separators.
+	Path string `json:"path"`
+	// Args stores arguments of the program, without program name.
+	Args []string `json:"args,omitempty"`
+	// Env stores additional environment variables in the "KEY=value" format,
+	// they are appended to the environment of the current process.
+	Env []string `json:"env,omitempty"`
+	// Dir is a working directory of the program, current directory if empty.
+	Dir string `json:"dir,omitempty"`
+	// MaxOutput limits how many bytes are captured from each of stdout and
+	// stderr, DefaultMaxOutput is used if it is zero and output is not captured
+	// at all if it is negative.
+	MaxOutput int `json:"max_output,omitempty"`
+}
+
+// outputWaitDelay limits how long Command waits for the output pipes to be
+// closed after the program has exited or has been killed.
+const outputWaitDelay = time.Second
+
+// CommandResult stores outcome of the single Command execution.
+type CommandResult struct {
+	// Stdout stores captured standard output.
+	Stdout string `json:"stdout"`
+	// Stderr stores captured standard error.
+	Stderr string `json:"stderr"`
+	// Truncated is true if any of outputs exceeded Command.MaxOutput.
+	Truncated bool `json:"truncated,omitempty"`
+	// ExitCode stores exit code of the program, it is -1 if program has not been
+	// started or it has been killed by signal.
+	ExitCode int `json:"exit_code"`
+}
+
+// NewCommand creates a new Command for the program with provided arguments.
+func NewCommand(path string, args ...string) *Command {
+	return &Command{Path: path, Args: args}
+}
+
+// Run executes the program once and waits for its completion. It returns
+// output of the program and an error if the program could not be started,
+// exited with non-zero code or has been killed because context was cancelled.
+func (command *Command) Run(ctx context.Context, task *Task) (*CommandResult, error) {
+	limit := command.MaxOutput
+	if limit == 0 {
+		limit = DefaultMaxOutput
+	}
+	stdout := &limitedBuffer{limit: limit}
+	stderr := &limitedBuffer{limit: limit}
+
+	process := exec.CommandContext(ctx, command.Path, command.Args...)
+	process.Dir = command.Dir
+	if len(command.Env) > 0 {
+		process.Env = append(os.Environ(), command.Env...)
+	}
+	process.Stdout = stdout
+	process.Stderr = stderr
+	setProcessGroup(process)
+	// Do not wait forever for the output of the background processes that
+	// outlived the program.
+	process.WaitDelay = outputWaitDelay
+
+	err := process.Run()
+
+	result := &CommandResult{
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+		ExitCode:  -1,
+	}
+	if process.ProcessState != nil {
+		result.ExitCode = process.ProcessState.ExitCode()
+	}
+
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("command %s has been killed: %w", command.Path, ctx.Err())
+	}
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		return result, fmt.Errorf("command %s exited with code %d: %w", command.Path, result.ExitCode, err)
+	}
+	return result, err
+}
+
+// limitedBuffer stores up to limit bytes and silently discards the rest, so the
+// program is not blocked by the full pipe.
+type limitedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write stores as much data as the limit allows, it always reports that all
+// data were written.
+func (buffer *limitedBuffer) Write(data []byte) (int, error) {
+	if buffer.limit < 0 {
+		return len(data), nil
+	}
+	stored := data
+	if available := buffer.limit - buffer.buffer.Len(); available < len(stored) {
+		buffer.truncated = true
+		stored = stored[:available]
+	}
+	buffer.buffer.Write(stored)
+	return len(data), nil
+}
+
+// String returns the stored data.
+func (buffer *limitedBuffer) String() string {
+	return buffer.buffer.String()
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package scheduler
+
+import "os/exec"
+
+// setProcessGroup keeps default behaviour on platforms without process groups,
+// cancellation kills only the started program.
+func setProcessGroup(process *exec.Cmd) {}
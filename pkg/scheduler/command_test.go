@@ -0,0 +1,119 @@
+//go:build unix
+
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RunCommand executes the command within the new run of the task and returns
+// the run record together with the returned error.
+func RunCommand(command *Command, ctx context.Context) (Run, error) {
+	task := NewSimpleTask("Command Task", time.Second)
+	err := task.execute(ctx, command.Run)
+	lastRun, _ := task.LastRun()
+	return lastRun, err
+}
+
+// TestCommand_Run tests that Command.Run captures output and exit code of the
+// successful program.
+func TestCommand_Run(t *testing.T) {
+	command := NewCommand("/bin/sh", "-c", "echo $GREETING; echo error >&2")
+	command.Env = []string{"GREETING=Hello"}
+
+	run, err := RunCommand(command, context.Background())
+	if err != nil {
+		t.Fatalf("Command has failed: %v.", err)
+	}
+
+	result, ok := run.Result.(*CommandResult)
+	if !ok {
+		t.Fatalf("Run result is not CommandResult: %v.", run.Result)
+	}
+
+	if result.Stdout != "Hello\n" || result.Stderr != "error\n" || result.ExitCode != 0 {
+		t.Fatalf("Incorrect command result: %+v.", result)
+	}
+
+	if run.Status != RunSucceeded {
+		t.Fatalf("Incorrect run status. Expected: %s. Actual: %s.", RunSucceeded, run.Status)
+	}
+}
+
+// TestCommand_Run_NonZeroExit tests that Command.Run treats non-zero exit code
+// as failure.
+func TestCommand_Run_NonZeroExit(t *testing.T) {
+	run, err := RunCommand(NewCommand("/bin/sh", "-c", "exit 3"), context.Background())
+	if err == nil {
+		t.Fatalf("Command with non-zero exit code has not failed.")
+	}
+
+	if result := run.Result.(*CommandResult); result.ExitCode != 3 {
+		t.Fatalf("Incorrect exit code. Expected: 3. Actual: %d.", result.ExitCode)
+	}
+
+	if run.Status != RunFailed || run.Err == nil {
+		t.Fatalf("Failed command has been recorded incorrectly: %+v.", run)
+	}
+}
+
+// TestCommand_Run_MaxOutput tests that Command.Run captures output only up to
+// Command.MaxOutput bytes.
+func TestCommand_Run_MaxOutput(t *testing.T) {
+	command := NewCommand("/bin/sh", "-c", "printf 0123456789")
+	command.MaxOutput = 4
+
+	run, err := RunCommand(command, context.Background())
+	if err != nil {
+		t.Fatalf("Command has failed: %v.", err)
+	}
+
+	if result := run.Result.(*CommandResult); result.Stdout != "0123" || !result.Truncated {
+		t.Fatalf("Output has not been truncated: %+v.", result)
+	}
+}
+
+// TestCommand_Run_Cancel tests that Command.Run kills the whole process group
+// when the context is cancelled.
+func TestCommand_Run_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	run, err := RunCommand(NewCommand("/bin/sh", "-c", "sleep 10 & sleep 10"), ctx)
+	elapsed := time.Since(started)
+
+	if err == nil || !strings.Contains(err.Error(), "killed") {
+		t.Fatalf("Cancelled command has not been reported as killed: %v.", err)
+	}
+
+	if elapsed > 2*time.Second {
+		t.Fatalf("Command has not been killed after cancellation, it took %s.", elapsed)
+	}
+
+	if result := run.Result.(*CommandResult); result.ExitCode != -1 {
+		t.Fatalf("Incorrect exit code of the killed command: %d.", result.ExitCode)
+	}
+}
+
+// TestCommand_Run_StopTask tests that stopping the scheduled task kills the
+// running command.
+func TestCommand_Run_StopTask(t *testing.T) {
+	newScheduler := CreateEmptyScheduler()
+	newTask := newScheduler.ScheduleTask("Command Task", nil, nil, time.Minute, NewCommand("/bin/sh", "-c", "sleep 10").Run)
+
+	time.Sleep(100 * time.Millisecond)
+	_ = newScheduler.StopTask(newTask)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if run, ok := newTask.LastRun(); ok && run.Status == RunFailed {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Command has not been killed after the task has been stopped.")
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package scheduler
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the program in its own process group and makes
+// cancellation kill the whole group, including child processes.
+func setProcessGroup(process *exec.Cmd) {
+	process.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	process.Cancel = func() error {
+		return syscall.Kill(-process.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,258 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears limits how far CronSchedule.Next looks for the matching time.
+const cronSearchYears = 5
+
+// CronSchedule is a parsed cron expression, it is set using Task.Cron.
+type CronSchedule struct {
+	// spec stores the original expression.
+	spec string
+	// seconds, minutes, hours, days, months and weekdays are bit sets of the
+	// allowed values.
+	seconds, minutes, hours, days, months, weekdays uint64
+	// anyDay and anyWeekday are set if the field is "*", when both day fields
+	// are restricted, the time matches if any of them matches.
+	anyDay, anyWeekday bool
+}
+
+// cronField describes the range and names of the cron field.
+type cronField struct {
+	name     string
+	minimum  int
+	maximum  int
+	names    map[string]int
+	wildcard bool
+}
+
+var (
+	cronSeconds  = cronField{name: "second", minimum: 0, maximum: 59}
+	cronMinutes  = cronField{name: "minute", minimum: 0, maximum: 59}
+	cronHours    = cronField{name: "hour", minimum: 0, maximum: 23}
+	cronDays     = cronField{name: "day of month", minimum: 1, maximum: 31}
+	cronMonths   = cronField{name: "month", minimum: 1, maximum: 12, names: cronNames("jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec")}
+	cronWeekdays = cronField{name: "day of week", minimum: 0, maximum: 7, names: cronNames("sun", "mon", "tue", "wed", "thu", "fri", "sat")}
+)
+
+// cronDescriptors maps predefined schedules to the cron expressions.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronNames maps names to their values, starting from the value of the first
+// name.
+func cronNames(names ...string) map[string]int {
+	values := make(map[string]int, len(names))
+	offset := 1
+	if names[0] == "sun" {
+		offset = 0
+	}
+	for index, name := range names {
+		values[name] = index + offset
+	}
+	return values
+}
+
+// ParseCron parses the cron expression. It supports five fields (minute, hour,
+// day of month, month, day of week) or six fields with seconds in front. Every
+// field accepts "*", values, ranges "1-5", lists "1,15" and steps "*/10" or
+// "0-30/5", months and days of week accept names like "jan" and "mon", Sunday
+// is 0 or 7. Descriptors "@yearly", "@annually", "@monthly", "@weekly",
+// "@daily", "@midnight" and "@hourly" are supported as well.
+func ParseCron(spec string) (*CronSchedule, error) {
+	expression := strings.TrimSpace(spec)
+	if descriptor, ok := cronDescriptors[strings.ToLower(expression)]; ok {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) == 5 {
+		fields = append([]string{"0"}, fields...)
+	}
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 or 6 fields, got %d", spec, len(fields))
+	}
+
+	schedule := &CronSchedule{spec: spec}
+	targets := []*uint64{&schedule.seconds, &schedule.minutes, &schedule.hours, &schedule.days, &schedule.months, &schedule.weekdays}
+	definitions := []cronField{cronSeconds, cronMinutes, cronHours, cronDays, cronMonths, cronWeekdays}
+	for index, field := range fields {
+		bits, err := definitions[index].parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", spec, err)
+		}
+		*targets[index] = bits
+	}
+
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+	schedule.anyDay = strings.HasPrefix(fields[3], "*")
+	schedule.anyWeekday = strings.HasPrefix(fields[5], "*")
+	return schedule, nil
+}
+
+// parse parses the comma-separated list of ranges of the field.
+func (field cronField) parse(text string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepText)
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step %q of the %s", stepText, field.name)
+			}
+			step = parsed
+		}
+
+		first, last := field.minimum, field.maximum
+		switch {
+		case rangeText == "*" || rangeText == "?":
+		case strings.Contains(rangeText, "-"):
+			firstText, lastText, _ := strings.Cut(rangeText, "-")
+			var err error
+			if first, err = field.value(firstText); err != nil {
+				return 0, err
+			}
+			if last, err = field.value(lastText); err != nil {
+				return 0, err
+			}
+		default:
+			value, err := field.value(rangeText)
+			if err != nil {
+				return 0, err
+			}
+			first = value
+			if !hasStep {
+				last = value
+			}
+		}
+		if first > last {
+			return 0, fmt.Errorf("invalid range %q of the %s", rangeText, field.name)
+		}
+
+		for value := first; value <= last; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value or name of the field and checks its range.
+func (field cronField) value(text string) (int, error) {
+	if value, ok := field.names[strings.ToLower(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q of the %s", text, field.name)
+	}
+	if value < field.minimum || value > field.maximum {
+		return 0, fmt.Errorf("value %d of the %s is out of range %d-%d", value, field.name, field.minimum, field.maximum)
+	}
+	return value, nil
+}
+
+// String returns the original cron expression.
+func (schedule *CronSchedule) String() string {
+	return schedule.spec
+}
+
+// Next returns the first time matching the schedule strictly after provided
+// time, in the location of provided time. It returns zero time if there is no
+// matching time within the next five years.
+//
+// Schedules with a restricted hour field are matched against the wall clock of
+// the location, so daylight saving time transitions are handled as follows:
+// a local time skipped by the transition is moved forward by the length of the
+// gap (e.g. 02:30 becomes 03:30), it is fired once even if the moved time
+// matches the schedule as well, and a repeated local time is matched only at
+// its first occurrence. Schedules matching every hour follow the elapsed time
+// instead, so they are fired during the repeated hour as well.
+func (schedule *CronSchedule) Next(after time.Time) time.Time {
+	current := after.Add(time.Second - time.Duration(after.Nanosecond()))
+	if schedule.hours == allHours {
+		return schedule.match(current)
+	}
+
+	location := after.Location()
+	wall := wallClock(current)
+	for {
+		if wall = schedule.match(wall); wall.IsZero() {
+			return wall
+		}
+		if next := localTime(wall, location); next.After(after) {
+			return next
+		}
+		wall = wall.Add(time.Second)
+	}
+}
+
+// allHours is the bit set of the hour field that matches every hour.
+const allHours = 1<<24 - 1
+
+// match returns the first time matching the schedule at or after the current
+// time, in its location. It returns zero time if there is no matching time
+// within the next five years.
+func (schedule *CronSchedule) match(current time.Time) time.Time {
+	location := current.Location()
+	limit := current.Year() + cronSearchYears
+
+	for current.Year() <= limit {
+		year, month, day := current.Date()
+		hour, minute, second := current.Clock()
+
+		if schedule.months&(1<<uint(month)) == 0 {
+			current = time.Date(year, month+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+		if !schedule.matchesDay(current) {
+			current = time.Date(year, month, day+1, 0, 0, 0, 0, location)
+			continue
+		}
+		if schedule.hours&(1<<uint(hour)) == 0 {
+			current = nextHour(current)
+			continue
+		}
+		if schedule.minutes&(1<<uint(minute)) == 0 {
+			current = current.Add(time.Minute - time.Duration(second)*time.Second)
+			continue
+		}
+		if schedule.seconds&(1<<uint(second)) == 0 {
+			current = current.Add(time.Second)
+			continue
+		}
+		return current
+	}
+	return time.Time{}
+}
+
+// nextHour returns the beginning of the next hour in the location of the
+// provided time.
+func nextHour(current time.Time) time.Time {
+	_, minute, second := current.Clock()
+	return current.Add(time.Hour - time.Duration(minute)*time.Minute - time.Duration(second)*time.Second)
+}
+
+// matchesDay checks whether day of month and day of week match the schedule.
+func (schedule *CronSchedule) matchesDay(current time.Time) bool {
+	day := schedule.days&(1<<uint(current.Day())) != 0
+	weekday := schedule.weekdays&(1<<uint(current.Weekday())) != 0
+	if schedule.anyDay || schedule.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
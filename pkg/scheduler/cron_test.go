@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseCron tests that ParseCron accepts supported syntax and rejects
+// invalid expressions.
+func TestParseCron(t *testing.T) {
+	for _, spec := range []string{"* * * * *", "*/15 9-17 * * mon-fri", "0 0 1,15 jan,jul *", "30 */10 * * * *", "@daily", "0 12 * * 7", "5/20 * * * *"} {
+		if _, err := ParseCron(spec); err != nil {
+			t.Fatalf("Cron expression %q has not been parsed. Error: %v.", spec, err)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "@often", "a * * * *"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Fatalf("Invalid cron expression %q has been parsed.", spec)
+		}
+	}
+}
+
+// TestCronSchedule_Next tests that CronSchedule.Next returns the first matching
+// time strictly after the provided time.
+func TestCronSchedule_Next(t *testing.T) {
+	after := time.Date(2024, 1, 31, 10, 7, 30, 0, time.UTC)
+
+	cases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 31, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 31, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * sat", time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * mon", time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)},
+		{"45 7 10 * * *", time.Date(2024, 1, 31, 10, 7, 45, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, testCase := range cases {
+		schedule, err := ParseCron(testCase.spec)
+		if err != nil {
+			t.Fatalf("Cron expression %q has not been parsed. Error: %v.", testCase.spec, err)
+		}
+		if next := schedule.Next(after); !next.Equal(testCase.expected) {
+			t.Fatalf("Incorrect next time of %q. Expected: %s. Actual: %s.", testCase.spec, testCase.expected, next)
+		}
+	}
+}
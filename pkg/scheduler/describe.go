@@ -0,0 +1,372 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxDescribedTimes limits how many times of day are listed by
+// CronSchedule.Describe, more hours are described as ranges.
+const maxDescribedTimes = 6
+
+// Describe returns the schedule of the task in plain English, e.g. "every 15
+// minutes between 08:00 and 18:00 on weekdays, until 2026-12-31".
+func (task *Task) Describe() string {
+	preview, err := task.preview()
+	if err != nil {
+		return fmt.Sprintf("invalid cron expression %q", task.Cron)
+	}
+
+	var description string
+	switch {
+	case preview.cron != nil:
+		description = preview.cron.Describe()
+	case preview.Interval > 0:
+		description = "every " + describeDuration(preview.Interval)
+		if preview.Interval%day == 0 && preview.Start != nil {
+			description += " at " + formatClock(preview.inLocation(*preview.Start))
+		}
+	default:
+		description = "once"
+		if preview.Start != nil {
+			description += " at " + formatMoment(preview.inLocation(*preview.Start))
+		}
+	}
+
+	if preview.Calendar != nil {
+		description += " only on " + describeCalendar(preview.Calendar)
+		if preview.CalendarPolicy == CalendarShift {
+			description += ", other days moved to the next one"
+		}
+	}
+	if preview.Jitter > 0 {
+		description += ", delayed randomly by up to " + preview.Jitter.String()
+	}
+	if preview.Location != nil {
+		description += ", in " + preview.Location.String() + " time"
+	}
+	if preview.Start != nil && !preview.once() {
+		description += ", starting " + formatMoment(preview.inLocation(*preview.Start))
+	}
+	if preview.Duration != nil {
+		if preview.Start != nil {
+			description += ", until " + formatMoment(preview.inLocation(preview.Start.Add(*preview.Duration)))
+		} else {
+			description += ", for " + preview.Duration.String()
+		}
+	}
+	return description
+}
+
+// Describe returns the schedule in plain English, e.g. "every 15 minutes
+// between 08:00 and 18:00 on weekdays".
+func (schedule *CronSchedule) Describe() string {
+	parts := []string{schedule.describeClock()}
+	days := schedule.describeDays()
+	if days != "" {
+		parts = append(parts, days)
+	}
+	months := bitValues(schedule.months, 1, 12)
+	if len(months) < 12 {
+		parts = append(parts, "in "+describeRuns(months, func(value int) string {
+			return time.Month(value).String()
+		}, " to "))
+	}
+	if strings.HasPrefix(parts[0], "at ") && days == "" && len(months) == 12 {
+		parts[0] = "every day " + parts[0]
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeClock describes the seconds, minutes and hours of the schedule.
+func (schedule *CronSchedule) describeClock() string {
+	seconds := bitValues(schedule.seconds, 0, 59)
+	minutes := bitValues(schedule.minutes, 0, 59)
+	hours := bitValues(schedule.hours, 0, 23)
+
+	if len(seconds) == 1 && len(minutes) == 1 && len(hours) <= maxDescribedTimes {
+		times := make([]string, 0, len(hours))
+		for _, hour := range hours {
+			times = append(times, formatClock(time.Date(0, 1, 1, hour, minutes[0], seconds[0], 0, time.UTC)))
+		}
+		return "at " + joinWords(times, "and")
+	}
+
+	var description string
+	secondStep := step(seconds, 0, 59)
+	switch {
+	case len(seconds) == 60:
+		description = "every second"
+	case secondStep > 0:
+		description = fmt.Sprintf("every %d seconds", secondStep)
+	}
+	if description != "" {
+		if len(minutes) < 60 {
+			description += " during minute" + plural(len(minutes)) + " " + describeRuns(minutes, strconv.Itoa, "-")
+		}
+		return description + schedule.describeHours(hours)
+	}
+
+	atSeconds := ""
+	if len(seconds) > 1 || seconds[0] != 0 {
+		atSeconds = "second" + plural(len(seconds)) + " " + describeRuns(seconds, strconv.Itoa, "-")
+	}
+	minuteStep := step(minutes, 0, 59)
+	switch {
+	case len(minutes) == 60:
+		description = "every minute"
+	case minuteStep > 0:
+		description = fmt.Sprintf("every %d minutes", minuteStep)
+	default:
+		description = "every hour"
+		if hourStep := step(hours, 0, 23); hourStep > 0 {
+			description, hours = fmt.Sprintf("every %d hours", hourStep), bitValues(allHours, 0, 23)
+		}
+		if len(minutes) > 1 || minutes[0] != 0 {
+			description += " at minute" + plural(len(minutes)) + " " + describeRuns(minutes, strconv.Itoa, "-")
+			if atSeconds != "" {
+				description += " and " + atSeconds
+			}
+			return description + schedule.describeHours(hours)
+		}
+	}
+	if atSeconds != "" {
+		description += " at " + atSeconds
+	}
+	return description + schedule.describeHours(hours)
+}
+
+// describeHours describes the hours when the schedule is fired as ranges,
+// every hour is not described.
+func (schedule *CronSchedule) describeHours(hours []int) string {
+	if len(hours) == 24 {
+		return ""
+	}
+	if hourStep := step(hours, 0, 23); hourStep > 0 {
+		return " during every " + ordinal(hourStep) + " hour"
+	}
+	ranges := make([]string, 0)
+	for _, run := range valueRuns(hours) {
+		end := "midnight"
+		if run[1] < 23 {
+			end = fmt.Sprintf("%02d:00", run[1]+1)
+		}
+		ranges = append(ranges, fmt.Sprintf("between %02d:00 and %s", run[0], end))
+	}
+	return " " + joinWords(ranges, "or")
+}
+
+// describeDays describes the days of month and days of week of the schedule,
+// it returns empty string if the schedule is fired every day.
+func (schedule *CronSchedule) describeDays() string {
+	parts := make([]string, 0, 2)
+	days := bitValues(schedule.days, 1, 31)
+	if len(days) < 31 {
+		if dayStep := step(days, 1, 31); dayStep > 0 && len(days) > 2 {
+			parts = append(parts, "on every "+ordinal(dayStep)+" day of the month")
+		} else {
+			parts = append(parts, "on day"+plural(len(days))+" "+describeRuns(days, strconv.Itoa, "-")+" of the month")
+		}
+	}
+	weekdays := bitValues(schedule.weekdays, 0, 6)
+	if len(weekdays) < 7 {
+		parts = append(parts, "on "+describeWeekdays(weekdays))
+	}
+	conjunction := " and "
+	if !schedule.anyDay && !schedule.anyWeekday {
+		conjunction = " or "
+	}
+	return strings.Join(parts, conjunction)
+}
+
+// describeWeekdays describes the days of week, e.g. "weekdays" or "Monday and
+// Friday".
+func describeWeekdays(weekdays []int) string {
+	switch {
+	case len(weekdays) == 0:
+		return "no days"
+	case len(weekdays) == 7:
+		return "every day"
+	case len(weekdays) == 5 && weekdays[0] == 1 && weekdays[4] == 5:
+		return "weekdays"
+	case len(weekdays) == 2 && weekdays[0] == 0 && weekdays[1] == 6:
+		return "weekends"
+	}
+	return describeRuns(weekdays, func(value int) string {
+		return time.Weekday(value).String()
+	}, " to ")
+}
+
+// String describes the days of the calendar, e.g. "weekdays".
+func (calendar *WeeklyCalendar) String() string {
+	weekdays := make([]int, 0, 7)
+	for weekday, selected := range calendar.days {
+		if selected {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+	return describeWeekdays(weekdays)
+}
+
+// String describes the dates of the calendar, a few dates are listed and more
+// dates are counted.
+func (calendar *DateCalendar) String() string {
+	dates := calendar.Dates()
+	if len(dates) == 0 || len(dates) > 3 {
+		return fmt.Sprintf("%d listed date%s", len(dates), plural(len(dates)))
+	}
+	formatted := make([]string, 0, len(dates))
+	for _, moment := range dates {
+		formatted = append(formatted, moment.Format(time.DateOnly))
+	}
+	return joinWords(formatted, "and")
+}
+
+// describeCalendar describes the days of the calendar using its String method,
+// if it implements fmt.Stringer.
+func describeCalendar(calendar Calendar) string {
+	if stringer, ok := calendar.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return "days of the calendar"
+}
+
+// describeDuration describes the duration in the largest whole unit, e.g.
+// "15 minutes" or "day".
+func describeDuration(duration time.Duration) string {
+	units := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"day", day},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+		{"millisecond", time.Millisecond},
+	}
+	for _, unit := range units {
+		if duration%unit.duration == 0 {
+			count := int(duration / unit.duration)
+			if count == 1 {
+				return unit.name
+			}
+			return fmt.Sprintf("%d %ss", count, unit.name)
+		}
+	}
+	return duration.String()
+}
+
+// formatClock formats the time of day, seconds are omitted if they are zero.
+func formatClock(moment time.Time) string {
+	if moment.Second() == 0 {
+		return moment.Format("15:04")
+	}
+	return moment.Format(time.TimeOnly)
+}
+
+// formatMoment formats the date, followed by the time of day if it is not
+// midnight.
+func formatMoment(moment time.Time) string {
+	if hour, minute, second := moment.Clock(); hour == 0 && minute == 0 && second == 0 {
+		return moment.Format(time.DateOnly)
+	}
+	return moment.Format(time.DateOnly) + " " + formatClock(moment)
+}
+
+// bitValues returns the values of the bit set between minimum and maximum.
+func bitValues(bits uint64, minimum int, maximum int) []int {
+	values := make([]int, 0)
+	for value := minimum; value <= maximum; value++ {
+		if bits&(1<<uint(value)) != 0 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// step returns the step of the values if they start at the minimum and
+// repeat evenly until the maximum, e.g. "*/15" minutes, zero otherwise.
+func step(values []int, minimum int, maximum int) int {
+	if len(values) < 2 || values[0] != minimum {
+		return 0
+	}
+	difference := values[1] - values[0]
+	if difference < 2 || values[len(values)-1]+difference <= maximum {
+		return 0
+	}
+	for index := 2; index < len(values); index++ {
+		if values[index]-values[index-1] != difference {
+			return 0
+		}
+	}
+	if minimum == 0 && (maximum+1)%difference != 0 {
+		return 0
+	}
+	return difference
+}
+
+// valueRuns groups the sorted values into runs of consecutive values, every
+// run is stored as its first and last value.
+func valueRuns(values []int) [][2]int {
+	runs := make([][2]int, 0)
+	for _, value := range values {
+		if last := len(runs) - 1; last >= 0 && runs[last][1] == value-1 {
+			runs[last][1] = value
+			continue
+		}
+		runs = append(runs, [2]int{value, value})
+	}
+	return runs
+}
+
+// describeRuns lists the values, at least three consecutive values are
+// described as the range joined by the separator, e.g. "1-7" or "Monday to
+// Friday".
+func describeRuns(values []int, format func(value int) string, separator string) string {
+	words := make([]string, 0, len(values))
+	for _, run := range valueRuns(values) {
+		switch {
+		case run[1]-run[0] >= 2:
+			words = append(words, format(run[0])+separator+format(run[1]))
+		case run[1] > run[0]:
+			words = append(words, format(run[0]), format(run[1]))
+		default:
+			words = append(words, format(run[0]))
+		}
+	}
+	return joinWords(words, "and")
+}
+
+// joinWords joins the words with commas and the conjunction before the last
+// one, e.g. "a, b and c".
+func joinWords(words []string, conjunction string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " " + conjunction + " " + words[len(words)-1]
+}
+
+// plural returns the plural suffix for the count.
+func plural(count int) string {
+	if count == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// ordinal formats the number as the English ordinal, e.g. "2nd".
+func ordinal(number int) string {
+	suffix := "th"
+	switch {
+	case number%100 >= 11 && number%100 <= 13:
+	case number%10 == 1:
+		suffix = "st"
+	case number%10 == 2:
+		suffix = "nd"
+	case number%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(number) + suffix
+}
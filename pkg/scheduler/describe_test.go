@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCronSchedule_Describe tests that common cron expressions are described
+// in plain English.
+func TestCronSchedule_Describe(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected string
+	}{
+		{"*/15 8-17 * * 1-5", "every 15 minutes between 08:00 and 18:00 on weekdays"},
+		{"* * * * *", "every minute"},
+		{"*/10 * * * * *", "every 10 seconds"},
+		{"@hourly", "every hour"},
+		{"30 * * * *", "every hour at minute 30"},
+		{"0 */2 * * *", "every 2 hours"},
+		{"5,35 * * * *", "every hour at minutes 5 and 35"},
+		{"@daily", "every day at 00:00"},
+		{"0 9,17 * * *", "every day at 09:00 and 17:00"},
+		{"30 15 9 * * sat,sun", "at 09:15:30 on weekends"},
+		{"0 6 * * mon-thu", "at 06:00 on Monday to Thursday"},
+		{"0 0 1,15 * *", "at 00:00 on days 1 and 15 of the month"},
+		{"0 0 1 1 *", "at 00:00 on day 1 of the month in January"},
+		{"0 12 1 * 1", "at 12:00 on day 1 of the month or on Monday"},
+		{"*/5 0-8,20-23 * * *", "every 5 minutes between 00:00 and 09:00 or between 20:00 and midnight"},
+	}
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			schedule, err := ParseCron(test.spec)
+			if err != nil {
+				t.Fatalf("Cron expression has not been parsed. Error: %v.", err)
+			}
+			if actual := schedule.Describe(); actual != test.expected {
+				t.Fatalf("Incorrect description: %q, expected: %q.", actual, test.expected)
+			}
+		})
+	}
+}
+
+// TestTask_Describe tests descriptions of interval, cron and calendar-based
+// tasks.
+func TestTask_Describe(t *testing.T) {
+	start := time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+	duration := end.Sub(start)
+	christmas := time.Date(2026, time.December, 25, 0, 0, 0, 0, time.UTC)
+
+	cronTask := &Task{Cron: "*/15 8-17 * * 1-5", Duration: &duration}
+	daily := &Task{Start: &start, Interval: 24 * time.Hour, Calendar: Except(BusinessDays, NewDateCalendar(christmas)), CalendarPolicy: CalendarShift}
+	once := &Task{Start: &start}
+	jittered := &Task{Interval: 90 * time.Minute, Jitter: 30 * time.Second, Location: time.UTC}
+	invalid := &Task{Cron: "invalid"}
+
+	tests := []struct {
+		name     string
+		task     *Task
+		expected string
+	}{
+		{"Cron", cronTask, "every 15 minutes between 08:00 and 18:00 on weekdays, for " + duration.String()},
+		{"Calendar", daily, "every day at 09:00 only on weekdays except 2026-12-25, other days moved to the next one, starting 2026-01-05 09:00"},
+		{"Once", once, "once at 2026-01-05 09:00"},
+		{"Jitter", jittered, "every 90 minutes, delayed randomly by up to 30s, in UTC time"},
+		{"Invalid", invalid, `invalid cron expression "invalid"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.task.Describe(); actual != test.expected {
+				t.Fatalf("Incorrect description: %q, expected: %q.", actual, test.expected)
+			}
+		})
+	}
+
+	cronTask.Start = &start
+	if actual := cronTask.Describe(); actual != "every 15 minutes between 08:00 and 18:00 on weekdays, starting 2026-01-05 09:00, until 2026-12-31" {
+		t.Fatalf("Incorrect description with the end: %q.", actual)
+	}
+}
@@ -0,0 +1,538 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTaskRunning is recorded as the reason of the skipped run, when the task
+// with OverlapSkip policy is due while its previous run is still in progress.
+var ErrTaskRunning = errors.New("previous run of the task is still in progress")
+
+// ErrSchedulerSaturated is recorded as the reason of the skipped run, when all
+// workers are busy, the queue is full and SaturationDrop policy is used.
+var ErrSchedulerSaturated = errors.New("all workers are busy and the queue is full")
+
+// dispatch is a run waiting in the queue for a free worker.
+type dispatch struct {
+	// task is the task to run.
+	task *Task
+	// scheduled stores the planned fire time of the run.
+	scheduled time.Time
+	// throttled stores how long the run has been delayed by rate limiters.
+	throttled time.Duration
+	// rank orders runs in the queue, run with the lower rank is executed first.
+	rank int64
+	// sequence orders runs with the same rank by the time they were queued.
+	sequence uint64
+	// group is the task group whose slot is taken by the run.
+	group *taskGroup
+	// held stores when the run started to wait for a slot in its group.
+	held time.Time
+	// triggered is set for the runs triggered by Scheduler.TriggerTask.
+	triggered bool
+	// parameters replace the parameters of the task, if hasParameters is set.
+	parameters []interface{}
+	// hasParameters is set if the run uses its own parameters.
+	hasParameters bool
+	// handle is finished once the run is finished or skipped, it could be nil.
+	handle *RunHandle
+}
+
+// dispatchQueue is a min-heap of runs ordered by their rank, it implements
+// heap.Interface.
+type dispatchQueue []dispatch
+
+// Len returns number of runs in the queue.
+func (queue dispatchQueue) Len() int {
+	return len(queue)
+}
+
+// Less reports whether the run with index i shall be executed before the run
+// with index j.
+func (queue dispatchQueue) Less(i, j int) bool {
+	if queue[i].rank != queue[j].rank {
+		return queue[i].rank < queue[j].rank
+	}
+	return queue[i].sequence < queue[j].sequence
+}
+
+// Swap swaps runs with provided indexes.
+func (queue dispatchQueue) Swap(i, j int) {
+	queue[i], queue[j] = queue[j], queue[i]
+}
+
+// Push adds run to the end of the queue.
+func (queue *dispatchQueue) Push(value interface{}) {
+	*queue = append(*queue, value.(dispatch))
+}
+
+// Pop removes the last run from the queue.
+func (queue *dispatchQueue) Pop() interface{} {
+	old := *queue
+	item := old[len(old)-1]
+	old[len(old)-1] = dispatch{}
+	*queue = old[:len(old)-1]
+	return item
+}
+
+// rank returns rank of the run of the task with provided priority queued at the
+// provided time. Without aging runs are ordered by priority only. With aging
+// every aging interval spent in the queue counts as one priority level, so the
+// rank is the time when the run would reach the zero priority. It doesn't
+// depend on the current time, which allows to keep runs in the heap.
+func rank(priority int, queued time.Time, aging time.Duration) int64 {
+	if aging <= 0 {
+		return -int64(priority)
+	}
+	return queued.UnixNano() - int64(priority)*int64(aging)
+}
+
+// taskHeap is a min-heap of tasks ordered by their next fire time, it
+// implements heap.Interface.
+type taskHeap []*Task
+
+// Len returns number of tasks in the heap.
+func (timers taskHeap) Len() int {
+	return len(timers)
+}
+
+// Less reports whether the task with index i shall be fired before the task
+// with index j, tasks due at the same time are fired by priority.
+func (timers taskHeap) Less(i, j int) bool {
+	if timers[i].nextFire.Equal(timers[j].nextFire) {
+		return timers[i].Priority > timers[j].Priority
+	}
+	return timers[i].nextFire.Before(timers[j].nextFire)
+}
+
+// Swap swaps tasks with provided indexes.
+func (timers taskHeap) Swap(i, j int) {
+	timers[i], timers[j] = timers[j], timers[i]
+	timers[i].heapIndex = i
+	timers[j].heapIndex = j
+}
+
+// Push adds task to the end of the heap.
+func (timers *taskHeap) Push(value interface{}) {
+	task := value.(*Task)
+	task.heapIndex = len(*timers)
+	*timers = append(*timers, task)
+}
+
+// Pop removes the last task from the heap.
+func (timers *taskHeap) Pop() interface{} {
+	old := *timers
+	task := old[len(old)-1]
+	old[len(old)-1] = nil
+	task.heapIndex = -1
+	*timers = old[:len(old)-1]
+	return task
+}
+
+// pushTimer adds task to the timers, or updates its position if it is already
+// there, and wakes up the timing loop if the task is the next one to fire. It
+// shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) pushTimer(task *Task) {
+	if scheduler.hasTimer(task) {
+		heap.Fix(&scheduler.timers, task.heapIndex)
+	} else {
+		heap.Push(&scheduler.timers, task)
+	}
+	if task.heapIndex == 0 {
+		scheduler.notify()
+	}
+}
+
+// removeTimer removes task from the timers. It shall be called with the
+// scheduler mutex locked.
+func (scheduler *Scheduler) removeTimer(task *Task) {
+	if scheduler.hasTimer(task) {
+		heap.Remove(&scheduler.timers, task.heapIndex)
+	}
+}
+
+// hasTimer checks whether task is in the timers. It shall be called with the
+// scheduler mutex locked.
+func (scheduler *Scheduler) hasTimer(task *Task) bool {
+	return task.heapIndex >= 0 && task.heapIndex < len(scheduler.timers) && scheduler.timers[task.heapIndex] == task
+}
+
+// notify wakes up the timing loop without blocking.
+func (scheduler *Scheduler) notify() {
+	select {
+	case scheduler.wake <- struct{}{}:
+	default:
+	}
+}
+
+// start launches the timing loop and workers, if they are not running yet. It
+// shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) start() {
+	if scheduler.started {
+		return
+	}
+	scheduler.started = true
+	go scheduler.loop()
+	for index := 0; index < scheduler.workers; index++ {
+		go scheduler.worker()
+	}
+}
+
+// loop is the timing loop, it waits until the earliest fire time and fires all
+// due tasks.
+func (scheduler *Scheduler) loop() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		scheduler.mutex.Lock()
+		if scheduler.shutdown {
+			scheduler.mutex.Unlock()
+			return
+		}
+		wait := time.Duration(-1)
+		for len(scheduler.timers) > 0 && !scheduler.shutdown {
+			now := time.Now()
+			task := scheduler.timers[0]
+			if task.nextFire.After(now) {
+				wait = task.nextFire.Sub(now)
+				break
+			}
+			heap.Pop(&scheduler.timers)
+			scheduler.fire(task, now)
+		}
+		scheduler.mutex.Unlock()
+
+		if wait < 0 {
+			<-scheduler.wake
+			continue
+		}
+
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-scheduler.wake:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		}
+	}
+}
+
+// fire handles the due task: it completes the task after its end, otherwise it
+// queues the run and puts the task back to the timers with the next fire time.
+// It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) fire(task *Task, now time.Time) {
+	if !task.end.IsZero() && !task.calendarTime(task.planned).Before(task.end) {
+		scheduler.complete(task)
+		return
+	}
+
+	if task.Overlap == OverlapSkip && scheduler.active[task] > 0 {
+		scheduler.skipRun(task, runPlan{scheduled: task.scheduledTime()}, ErrTaskRunning)
+	} else if !scheduler.enqueue(task, now) {
+		return
+	}
+
+	if task.scheduler != scheduler {
+		// Task has been stopped while the timing loop waited for the queue.
+		return
+	}
+
+	if !task.fired {
+		task.fired = true
+		scheduler.emit(EventTaskStarted, task, nil)
+	}
+	task.last = task.planned
+	task.planned = task.nextPlanned(task.planned, now)
+	if missed := task.missed(task.last, task.planned); missed > 0 {
+		scheduler.emitMisfire(task, missed)
+	}
+	if task.paused.Load() {
+		return
+	}
+	scheduler.reschedule(task)
+}
+
+// reschedule puts the task back to the timers with its planned fire time, or
+// with its end time if the task ends before. The task that is not fired
+// anymore is completed. It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) reschedule(task *Task) {
+	if task.planned.IsZero() {
+		scheduler.complete(task)
+		return
+	}
+	if !task.end.IsZero() && !task.calendarTime(task.planned).Before(task.end) {
+		task.nextFire = task.end
+	} else {
+		task.nextFire = task.scheduledTime()
+	}
+	scheduler.pushTimer(task)
+}
+
+// PauseTask suspends firing of the scheduled task until ResumeTask is called,
+// fire times that pass meanwhile are skipped without being recorded. The
+// running execution is not affected.
+func (scheduler *Scheduler) PauseTask(task *Task) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return scheduler.pause(task)
+}
+
+// pause suspends firing of the task, it shall be called with the scheduler
+// mutex locked.
+func (scheduler *Scheduler) pause(task *Task) error {
+	if task.scheduler != scheduler {
+		return fmt.Errorf("task with id: %s cannot be paused, because it was not found", task.ID)
+	}
+	if task.paused.Swap(true) {
+		return nil
+	}
+	scheduler.removeTimer(task)
+	task.throttled = time.Time{}
+	return nil
+}
+
+// Paused checks whether firing of the task is suspended by Scheduler.PauseTask.
+func (task *Task) Paused() bool {
+	return task.paused.Load()
+}
+
+// ResumeTask continues firing of the paused task from the first fire time that
+// is not in the past, the task that runs only once is fired immediately if its
+// fire time has passed.
+func (scheduler *Scheduler) ResumeTask(task *Task) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return scheduler.resume(task, time.Now())
+}
+
+// resume continues firing of the paused task, it shall be called with the
+// scheduler mutex locked.
+func (scheduler *Scheduler) resume(task *Task, now time.Time) error {
+	if task.scheduler != scheduler {
+		return fmt.Errorf("task with id: %s cannot be resumed, because it was not found", task.ID)
+	}
+	if !task.paused.Swap(false) {
+		return nil
+	}
+	if task.planned.Before(now) && !task.once() {
+		task.planned = task.nextPlanned(task.planned, now)
+	}
+	scheduler.reschedule(task)
+	return nil
+}
+
+// enqueue puts the run of the task to the queue according to the saturation
+// policy and rate limiters of the task. It returns false if the run has been
+// postponed and task has already been put back to the timers. It shall be
+// called with the scheduler mutex locked.
+func (scheduler *Scheduler) enqueue(task *Task, now time.Time) bool {
+	for scheduler.saturated() {
+		switch scheduler.saturation {
+		case SaturationDrop:
+			scheduler.skipRun(task, runPlan{scheduled: task.scheduledTime()}, ErrSchedulerSaturated)
+			return true
+		case SaturationDelay:
+			task.nextFire = now.Add(scheduler.saturationDelay)
+			scheduler.pushTimer(task)
+			return false
+		default:
+			scheduler.space.Wait()
+			if scheduler.shutdown || task.scheduler != scheduler || task.paused.Load() {
+				// Pass the wakeup on, so that it is not lost for other waiters.
+				scheduler.space.Signal()
+				return true
+			}
+		}
+	}
+
+	now = time.Now()
+	wait, skip := scheduler.throttle(task, now)
+	if wait > 0 && skip {
+		task.throttled = time.Time{}
+		scheduler.skipRun(task, runPlan{scheduled: task.scheduledTime()}, ErrRateLimited)
+		return true
+	}
+	if wait > 0 {
+		if task.throttled.IsZero() {
+			task.throttled = now
+		}
+		task.nextFire = now.Add(wait)
+		scheduler.pushTimer(task)
+		return false
+	}
+
+	item := dispatch{task: task, scheduled: task.scheduledTime()}
+	if !task.throttled.IsZero() {
+		item.throttled = now.Sub(task.throttled)
+		task.throttled = time.Time{}
+	}
+	scheduler.push(item, now)
+	return true
+}
+
+// push puts the run to the queue and wakes up a worker. It shall be called with
+// the scheduler mutex locked.
+func (scheduler *Scheduler) push(item dispatch, now time.Time) {
+	scheduler.sequence++
+	item.rank = rank(item.task.Priority, now, scheduler.aging)
+	item.sequence = scheduler.sequence
+	heap.Push(&scheduler.queue, item)
+	scheduler.active[item.task]++
+	scheduler.observeWorkers()
+	scheduler.work.Signal()
+}
+
+// saturated checks whether new run cannot be queued, because all workers are
+// busy and the queue is full. Free workers are counted from the busy ones, so
+// workers that have not entered their loop yet are free as well. It shall be
+// called with the scheduler mutex locked.
+func (scheduler *Scheduler) saturated() bool {
+	return len(scheduler.queue) >= scheduler.queueSize+scheduler.workers-scheduler.busy
+}
+
+// nextFireTime returns the first regular fire time after the planned one that
+// is not in the past, missed fire times are skipped.
+func nextFireTime(planned time.Time, interval time.Duration, now time.Time) time.Time {
+	next := planned.Add(interval)
+	if next.Before(now) {
+		missed := now.Sub(planned) / interval
+		next = planned.Add((missed + 1) * interval)
+	}
+	return next
+}
+
+// complete removes the task that reached its end from the scheduler. Task.Wait
+// returns once all its runs are finished. It shall be called with the
+// scheduler mutex locked.
+func (scheduler *Scheduler) complete(task *Task) {
+	if scheduler.removeTask(task) == nil {
+		scheduler.emit(EventTaskCompleted, task, nil)
+	}
+	if scheduler.active[task] == 0 {
+		task.closeStopSignal()
+	}
+}
+
+// worker executes queued runs until the scheduler is shut down.
+func (scheduler *Scheduler) worker() {
+	for {
+		scheduler.mutex.Lock()
+		for len(scheduler.queue) == 0 && !scheduler.shutdown {
+			scheduler.work.Wait()
+		}
+		if scheduler.shutdown {
+			scheduler.mutex.Unlock()
+			return
+		}
+		item := heap.Pop(&scheduler.queue).(dispatch)
+		scheduler.space.Signal()
+		if !scheduler.acquire(&item, time.Now()) {
+			scheduler.mutex.Unlock()
+			continue
+		}
+		scheduler.executing.Add(1)
+		scheduler.busy++
+		scheduler.observeWorkers()
+		scheduler.mutex.Unlock()
+
+		scheduler.run(item)
+	}
+}
+
+// run executes the queued run, unless the task has been stopped meanwhile.
+func (scheduler *Scheduler) run(item dispatch) {
+	defer scheduler.executing.Done()
+
+	task := item.task
+	parameters := task.parameters
+	if item.hasParameters {
+		parameters = item.parameters
+	}
+	if err := task.ctx.Err(); err == nil {
+		plan := runPlan{scheduled: item.scheduled, throttled: item.throttled, triggered: item.triggered, handle: item.handle, scheduler: scheduler}
+		_ = task.executeScheduled(task.ctx, plan, task.function, parameters...)
+	} else {
+		run := Run{Scheduled: item.scheduled, Status: RunSkipped, Err: err, Triggered: item.triggered}
+		scheduler.emit(EventRunSkipped, task, &run)
+		item.handle.finish(run)
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	scheduler.busy--
+	scheduler.space.Signal()
+	scheduler.observeWorkers()
+	scheduler.releaseGroup(item)
+	scheduler.release(task)
+}
+
+// release decreases number of active runs of the task, once there are no more
+// runs of the task that has been completed or stopped, it closes its stop
+// signal. It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) release(task *Task) {
+	scheduler.active[task]--
+	if scheduler.active[task] <= 0 {
+		delete(scheduler.active, task)
+		if task.scheduler == nil {
+			task.closeStopSignal()
+		}
+	}
+}
+
+// Shutdown stops the scheduler: tasks are not fired anymore, queued runs and
+// runs held by task groups are dropped and it waits until the running
+// executions are finished. If the context is done before that, it cancels the
+// contexts of the running executions, waits for them and returns the context
+// error. All tasks are stopped afterwards, listeners and subscriptions are
+// removed and no new tasks could be scheduled.
+func (scheduler *Scheduler) Shutdown(ctx context.Context) error {
+	scheduler.mutex.Lock()
+	scheduler.shutdown = true
+	for _, item := range scheduler.queue {
+		scheduler.skipDispatch(item, ErrSchedulerShutdown)
+		scheduler.release(item.task)
+		if item.group != nil {
+			item.group.stats.Running--
+		}
+	}
+	scheduler.queue = nil
+	scheduler.dropHeld(ErrSchedulerShutdown)
+	tasks := append([]*Task(nil), scheduler.Tasks...)
+	running := make([]*Task, 0, len(scheduler.active))
+	for task := range scheduler.active {
+		running = append(running, task)
+	}
+	scheduler.work.Broadcast()
+	scheduler.space.Broadcast()
+	scheduler.notify()
+	scheduler.mutex.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		scheduler.executing.Wait()
+		close(finished)
+	}()
+
+	var err error
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		err = ctx.Err()
+		for _, task := range running {
+			task.cancel()
+		}
+		<-finished
+	}
+
+	for _, task := range tasks {
+		_ = scheduler.StopTask(task)
+	}
+	scheduler.events.close()
+	return err
+}
@@ -0,0 +1,327 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ConcurrencyCounter tracks the maximum number of concurrent executions.
+type ConcurrencyCounter struct {
+	mutex   sync.Mutex
+	running int
+	maximum int
+}
+
+// Run increases number of concurrent executions for the provided duration.
+func (counter *ConcurrencyCounter) Run(duration time.Duration) {
+	counter.mutex.Lock()
+	counter.running++
+	if counter.running > counter.maximum {
+		counter.maximum = counter.running
+	}
+	counter.mutex.Unlock()
+
+	time.Sleep(duration)
+
+	counter.mutex.Lock()
+	counter.running--
+	counter.mutex.Unlock()
+}
+
+// Maximum returns the maximum number of concurrent executions.
+func (counter *ConcurrencyCounter) Maximum() int {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+	return counter.maximum
+}
+
+// CountRuns returns number of runs in the task history with provided status.
+func CountRuns(task *Task, status RunStatus) int {
+	count := 0
+	for _, run := range task.History() {
+		if run.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// TestScheduler_Schedule tests that Scheduler.Schedule runs prepared task with
+// its attributes and one-shot task completes after the first run.
+func TestScheduler_Schedule(t *testing.T) {
+	newScheduler := New()
+	task := NewSimpleTask("One Shot", 0)
+	task.SetToContext("value", 42)
+
+	result := make(chan interface{}, 1)
+	if err := newScheduler.Schedule(task, func(task *Task) { result <- task.GetFromContext("value") }); err != nil {
+		t.Fatalf("Task has not been scheduled: %v.", err)
+	}
+
+	task.Wait()
+
+	if value := <-result; value != 42 {
+		t.Fatalf("Task context has not been passed to the run. Expected: 42. Actual: %v.", value)
+	}
+
+	if len(task.History()) != 1 || newScheduler.FindTaskByID(task.ID) != nil {
+		t.Fatalf("One-shot task has not been completed after the first run: %v.", task.History())
+	}
+}
+
+// TestScheduler_Schedule_Invalid tests that Scheduler.Schedule rejects
+// non-function, already scheduled task and scheduling after shutdown.
+func TestScheduler_Schedule_Invalid(t *testing.T) {
+	newScheduler := New()
+	task := NewSimpleTask("Task", time.Hour)
+
+	if err := newScheduler.Schedule(task, "not a function"); err == nil {
+		t.Fatalf("Non-function has been scheduled.")
+	}
+
+	if err := newScheduler.Schedule(task, func(task *Task) {}); err != nil {
+		t.Fatalf("Task has not been scheduled: %v.", err)
+	}
+
+	if err := newScheduler.Schedule(task, func(task *Task) {}); err == nil {
+		t.Fatalf("Task has been scheduled twice.")
+	}
+
+	if err := newScheduler.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Scheduler has not been shut down: %v.", err)
+	}
+
+	if err := newScheduler.Schedule(NewSimpleTask("Task", time.Hour), func(task *Task) {}); !errors.Is(err, ErrSchedulerShutdown) {
+		t.Fatalf("Task has been scheduled after shutdown: %v.", err)
+	}
+
+	if newScheduler.ScheduleTask("Task", nil, nil, time.Hour, func(task *Task) {}) != nil {
+		t.Fatalf("ScheduleTask has returned task after shutdown.")
+	}
+}
+
+// TestScheduler_WithWorkers tests that Scheduler executes at most configured
+// number of runs at the same time.
+func TestScheduler_WithWorkers(t *testing.T) {
+	counter := &ConcurrencyCounter{}
+	newScheduler := New(WithWorkers(2))
+	duration := 250 * time.Millisecond
+
+	tasks := make([]*Task, 0)
+	for index := 0; index < 5; index++ {
+		tasks = append(tasks, newScheduler.ScheduleTask("Task", nil, &duration, time.Hour, func(task *Task) {
+			counter.Run(50 * time.Millisecond)
+		}))
+	}
+
+	for _, task := range tasks {
+		task.Wait()
+	}
+
+	if counter.Maximum() != 2 {
+		t.Fatalf("Incorrect number of concurrent runs. Expected: 2. Actual: %d.", counter.Maximum())
+	}
+
+	for _, task := range tasks {
+		if CountRuns(task, RunSucceeded) != 1 {
+			t.Fatalf("Task has not been executed once: %v.", task.History())
+		}
+	}
+}
+
+// TestScheduler_Overlap tests that task with OverlapSkip policy skips runs while
+// its previous run is in progress and task with OverlapAllow runs them
+// concurrently.
+func TestScheduler_Overlap(t *testing.T) {
+	for _, policy := range []OverlapPolicy{OverlapSkip, OverlapAllow} {
+		counter := &ConcurrencyCounter{}
+		newScheduler := New()
+		duration := 300 * time.Millisecond
+
+		task := NewTask("", "Overlap", nil, &duration, 50*time.Millisecond, nil, nil)
+		task.Overlap = policy
+		_ = newScheduler.Schedule(task, func(task *Task) {
+			counter.Run(120 * time.Millisecond)
+		})
+		task.Wait()
+
+		if policy == OverlapSkip {
+			if counter.Maximum() != 1 || CountRuns(task, RunSkipped) == 0 {
+				t.Fatalf("Overlapping runs have not been skipped: %d concurrent runs, history: %v.", counter.Maximum(), task.History())
+			}
+			run := task.History()[1]
+			if run.Status != RunSkipped || !errors.Is(run.Err, ErrTaskRunning) {
+				t.Fatalf("Skipped run has been recorded incorrectly: %+v.", run)
+			}
+		} else if counter.Maximum() < 2 || CountRuns(task, RunSkipped) != 0 {
+			t.Fatalf("Overlapping runs have not been executed concurrently: %d concurrent runs, history: %v.", counter.Maximum(), task.History())
+		}
+	}
+}
+
+// TestScheduler_SaturationPolicy tests that runs are dropped or delayed when all
+// workers are busy and the queue is full.
+func TestScheduler_SaturationPolicy(t *testing.T) {
+	for _, policy := range []SaturationPolicy{SaturationDrop, SaturationDelay} {
+		newScheduler := New(WithWorkers(1), WithQueueSize(0), WithSaturationPolicy(policy, 100*time.Millisecond))
+
+		busy := NewSimpleTask("Busy", 0)
+		waiting := NewSimpleTask("Waiting", 0)
+		started := make(chan struct{})
+		_ = newScheduler.Schedule(busy, func(task *Task) {
+			close(started)
+			time.Sleep(150 * time.Millisecond)
+		})
+		// The first run on the fresh scheduler is executed, even if the worker
+		// has not been waiting for it yet.
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatalf("First run has not been started with the free worker.")
+		}
+		_ = newScheduler.Schedule(waiting, func(task *Task) {})
+
+		busy.Wait()
+		if run, _ := busy.LastRun(); run.Status != RunSucceeded {
+			t.Fatalf("First run has not been executed: %+v.", run)
+		}
+		if policy == SaturationDrop {
+			waiting.Wait()
+			run, _ := waiting.LastRun()
+			if run.Status != RunSkipped || !errors.Is(run.Err, ErrSchedulerSaturated) {
+				t.Fatalf("Run has not been dropped on saturation: %+v.", run)
+			}
+			continue
+		}
+
+		waiting.Wait()
+		run, _ := waiting.LastRun()
+		if run.Status != RunSucceeded || run.Started.Sub(run.Scheduled) < 100*time.Millisecond {
+			t.Fatalf("Run has not been delayed on saturation: %+v.", run)
+		}
+	}
+}
+
+// TestScheduler_Shutdown tests that Scheduler.Shutdown waits for the running
+// executions and cancels them when the context is done.
+func TestScheduler_Shutdown(t *testing.T) {
+	newScheduler := New()
+	finished := make(chan struct{})
+	task := newScheduler.ScheduleTask("Task", nil, nil, time.Hour, func(task *Task) {
+		time.Sleep(100 * time.Millisecond)
+		close(finished)
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	if err := newScheduler.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Scheduler has not been shut down: %v.", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Fatalf("Shutdown has not waited for the running execution.")
+	}
+
+	task.Wait()
+	if len(newScheduler.Tasks) != 0 {
+		t.Fatalf("Tasks have not been stopped after shutdown: %v.", newScheduler.Tasks)
+	}
+
+	newScheduler = New()
+	cancelled := newScheduler.ScheduleTask("Task", nil, nil, time.Hour, func(ctx context.Context, task *Task) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := newScheduler.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown has not reported exceeded deadline: %v.", err)
+	}
+
+	if run, _ := cancelled.LastRun(); run.Status != RunFailed {
+		t.Fatalf("Running execution has not been cancelled: %+v.", run)
+	}
+}
+
+// TestScheduler_Priority tests that runs waiting for a free worker are executed
+// by priority of their tasks.
+func TestScheduler_Priority(t *testing.T) {
+	newScheduler := New(WithWorkers(1))
+	defer newScheduler.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	blocker := newScheduler.ScheduleTask("Blocker", nil, nil, 0, func(task *Task) {
+		<-release
+	})
+
+	var mutex sync.Mutex
+	order := make([]int, 0)
+	start := time.Now().Add(50 * time.Millisecond)
+	tasks := make([]*Task, 0)
+	for _, priority := range []int{PriorityLow, PriorityNormal, PriorityHigh} {
+		task := NewTask("", "Task", &start, nil, 0, nil, nil)
+		task.Priority = priority
+		_ = newScheduler.Schedule(task, func(task *Task) {
+			mutex.Lock()
+			order = append(order, task.Priority)
+			mutex.Unlock()
+		})
+		tasks = append(tasks, task)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	blocker.Wait()
+	for _, task := range tasks {
+		task.Wait()
+	}
+
+	expected := []int{PriorityHigh, PriorityNormal, PriorityLow}
+	for index := range expected {
+		if order[index] != expected[index] {
+			t.Fatalf("Incorrect order of runs. Expected: %v. Actual: %v.", expected, order)
+		}
+	}
+}
+
+// TestRank tests that queued runs are ordered by priority and low-priority runs
+// overtake high-priority runs after waiting long enough.
+func TestRank(t *testing.T) {
+	queued := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if rank(PriorityHigh, queued, 0) >= rank(PriorityLow, queued, 0) {
+		t.Fatalf("High-priority run is not ranked before low-priority run without aging.")
+	}
+
+	if rank(PriorityHigh, queued.Add(time.Hour), 0) >= rank(PriorityLow, queued, 0) {
+		t.Fatalf("Low-priority run has been aged without aging.")
+	}
+
+	if rank(PriorityHigh, queued.Add(5*time.Second), time.Second) >= rank(PriorityLow, queued, time.Second) {
+		t.Fatalf("High-priority run is not ranked before low-priority run, which waits shortly.")
+	}
+
+	if rank(PriorityHigh, queued.Add(25*time.Second), time.Second) <= rank(PriorityLow, queued, time.Second) {
+		t.Fatalf("Low-priority run has not overtaken high-priority run after aging.")
+	}
+}
+
+// TestNextFireTime tests that nextFireTime skips missed fire times.
+func TestNextFireTime(t *testing.T) {
+	planned := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if next := nextFireTime(planned, time.Minute, planned); !next.Equal(planned.Add(time.Minute)) {
+		t.Fatalf("Incorrect next fire time: %s.", next)
+	}
+
+	if next := nextFireTime(planned, time.Minute, planned.Add(150*time.Second)); !next.Equal(planned.Add(3 * time.Minute)) {
+		t.Fatalf("Missed fire times have not been skipped: %s.", next)
+	}
+}
@@ -0,0 +1,260 @@
+package scheduler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultListenerBuffer is the number of events buffered for every listener, if
+// other size was not provided to Scheduler.Subscribe.
+const DefaultListenerBuffer = 256
+
+// EventType represents the kind of the scheduler activity.
+type EventType string
+
+const (
+	// EventTaskAdded is emitted when the task is scheduled.
+	EventTaskAdded EventType = "task_added"
+	// EventTaskRemoved is emitted when the task is stopped or replaced.
+	EventTaskRemoved EventType = "task_removed"
+	// EventTaskStarted is emitted when the task is fired for the first time.
+	EventTaskStarted EventType = "task_started"
+	// EventRunStarted is emitted when the execution starts.
+	EventRunStarted EventType = "run_started"
+	// EventRunSucceeded is emitted when the function returns without an error.
+	EventRunSucceeded EventType = "run_succeeded"
+	// EventRunFailed is emitted when the function returns an error.
+	EventRunFailed EventType = "run_failed"
+	// EventRunSkipped is emitted when the run is skipped, Run.Err stores the
+	// reason.
+	EventRunSkipped EventType = "run_skipped"
+	// EventMisfired is emitted when fire times of the task have passed without
+	// being fired, for example because the scheduler was busy. Event.Missed
+	// stores the number of missed fire times.
+	EventMisfired EventType = "misfired"
+	// EventTaskCompleted is emitted when the task reaches its end and is
+	// removed from the scheduler.
+	EventTaskCompleted EventType = "task_completed"
+)
+
+// Event describes the scheduler activity.
+type Event struct {
+	// Type is the kind of the activity.
+	Type EventType
+	// Time is when the event happened.
+	Time time.Time
+	// Task is the task the event belongs to.
+	Task *Task
+	// Run is a copy of the run record for the run events, nil otherwise.
+	Run *Run
+	// Missed is the number of missed fire times for EventMisfired.
+	Missed int
+}
+
+// Listener receives events about the scheduler activity.
+type Listener interface {
+	// OnEvent is called for every event, it is called from the goroutine that
+	// belongs to the listener, so events are received in order.
+	OnEvent(event Event)
+}
+
+// ListenerFunc is a function that implements Listener.
+type ListenerFunc func(event Event)
+
+// OnEvent calls the function.
+func (function ListenerFunc) OnEvent(event Event) {
+	function(event)
+}
+
+// subscription delivers events to the single listener.
+type subscription struct {
+	// listener receives the events, it is nil for channel subscriptions.
+	listener Listener
+	// events buffers events that are not delivered yet.
+	events chan Event
+	// closed is closed when the subscription is removed.
+	closed chan struct{}
+	// once guards closing of closed.
+	once sync.Once
+}
+
+// eventBus stores subscriptions of the Scheduler.
+type eventBus struct {
+	// subscriptions stores active subscriptions.
+	subscriptions map[*subscription]struct{}
+	// closed is set once the Scheduler is shut down, new subscriptions are
+	// closed immediately afterwards.
+	closed bool
+	// mutex guards subscriptions and closed.
+	mutex sync.RWMutex
+	// dropped counts events dropped because the buffer of some subscription
+	// was full.
+	dropped atomic.Int64
+}
+
+// WithListener registers the listener on the Scheduler, see
+// Scheduler.AddListener.
+func WithListener(listener Listener) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.AddListener(listener)
+	}
+}
+
+// AddListener registers the listener, which receives all events of the
+// Scheduler. Events are delivered asynchronously, so a slow listener doesn't
+// block the scheduler, events are dropped if its buffer of
+// DefaultListenerBuffer events is full, see Scheduler.DroppedEvents. Panics of
+// the listener are recovered. The listener is removed, once the Scheduler is
+// shut down. It returns a function that removes the listener.
+func (scheduler *Scheduler) AddListener(listener Listener) func() {
+	item := scheduler.events.add(listener, DefaultListenerBuffer)
+	go item.deliver()
+	return func() {
+		scheduler.events.remove(item)
+	}
+}
+
+// Subscribe returns a channel that receives all events of the Scheduler, events
+// are dropped if the channel buffer of provided size is full. The returned
+// function cancels the subscription and closes the channel, it is closed as
+// well once the Scheduler is shut down.
+func (scheduler *Scheduler) Subscribe(buffer int) (<-chan Event, func()) {
+	if buffer <= 0 {
+		buffer = DefaultListenerBuffer
+	}
+	item := scheduler.events.add(nil, buffer)
+	return item.events, func() {
+		scheduler.events.remove(item)
+	}
+}
+
+// add creates a new subscription.
+func (bus *eventBus) add(listener Listener, buffer int) *subscription {
+	item := &subscription{listener: listener, events: make(chan Event, buffer), closed: make(chan struct{})}
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.subscriptions == nil {
+		bus.subscriptions = make(map[*subscription]struct{})
+	}
+	bus.subscriptions[item] = struct{}{}
+	if bus.closed {
+		bus.cancel(item)
+	}
+	return item
+}
+
+// remove cancels the subscription.
+func (bus *eventBus) remove(item *subscription) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if _, ok := bus.subscriptions[item]; ok {
+		bus.cancel(item)
+	}
+}
+
+// close cancels all subscriptions, listeners are called only for the events
+// that are already buffered.
+func (bus *eventBus) close() {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.closed = true
+	for item := range bus.subscriptions {
+		bus.cancel(item)
+	}
+}
+
+// cancel removes the subscription and closes it, it shall be called with the
+// bus mutex locked.
+func (bus *eventBus) cancel(item *subscription) {
+	delete(bus.subscriptions, item)
+	item.once.Do(func() {
+		close(item.closed)
+		if item.listener == nil {
+			close(item.events)
+		}
+	})
+}
+
+// publish sends the event to all subscriptions without blocking.
+func (bus *eventBus) publish(event Event) {
+	bus.mutex.RLock()
+	defer bus.mutex.RUnlock()
+	for item := range bus.subscriptions {
+		select {
+		case item.events <- event:
+		default:
+			bus.dropped.Add(1)
+		}
+	}
+}
+
+// deliver calls the listener for every buffered event until the subscription
+// is removed, events published before the removal are still delivered.
+func (item *subscription) deliver() {
+	for {
+		select {
+		case event := <-item.events:
+			item.call(event)
+		case <-item.closed:
+			for {
+				select {
+				case event := <-item.events:
+					item.call(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// call calls the listener and recovers its panic.
+func (item *subscription) call(event Event) {
+	defer func() {
+		_ = recover()
+	}()
+	item.listener.OnEvent(event)
+}
+
+// emit publishes the event about the task, run is copied if it is not nil. It
+// could be called with nil scheduler, for example for the tasks executed by
+// the Workflow.
+func (scheduler *Scheduler) emit(eventType EventType, task *Task, run *Run) {
+	if scheduler == nil {
+		return
+	}
+	event := Event{Type: eventType, Time: time.Now(), Task: task}
+	if run != nil {
+		copied := *run
+		event.Run = &copied
+	}
+	scheduler.publish(event)
+}
+
+// emitMisfire publishes EventMisfired with the number of missed fire times.
+func (scheduler *Scheduler) emitMisfire(task *Task, missed int) {
+	scheduler.publish(Event{Type: EventMisfired, Time: time.Now(), Task: task, Missed: missed})
+}
+
+// publish logs the event, reports it to the metrics and sends it to listeners and subscriptions.
+func (scheduler *Scheduler) publish(event Event) {
+	scheduler.logEvent(event)
+	scheduler.observeEvent(event)
+	scheduler.events.publish(event)
+}
+
+// DroppedEvents returns the number of events that have not been delivered to
+// the listeners and subscriptions of the Scheduler, because their buffer was
+// full.
+func (scheduler *Scheduler) DroppedEvents() int64 {
+	return scheduler.events.dropped.Load()
+}
+
+// skipRun records the skipped run in the task history and emits
+// EventRunSkipped.
+func (scheduler *Scheduler) skipRun(task *Task, plan runPlan, reason error) Run {
+	run := task.skipRun(plan, reason)
+	scheduler.emit(EventRunSkipped, task, &run)
+	return run
+}
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestScheduler_AddListener tests that listeners receive events about the task
+// and its runs and that panicking listener doesn't affect the others.
+func TestScheduler_AddListener(t *testing.T) {
+	events := make(chan Event, 16)
+	newScheduler := New(WithListener(ListenerFunc(func(event Event) {
+		panic("listener failed")
+	})))
+	defer newScheduler.Shutdown(context.Background())
+	remove := newScheduler.AddListener(ListenerFunc(func(event Event) {
+		events <- event
+	}))
+	defer remove()
+
+	failure := errors.New("failure")
+	task := newScheduler.ScheduleTask("Task", nil, nil, 0, func(task *Task) error {
+		return failure
+	})
+
+	received := make(map[EventType]Event)
+	timeout := time.After(time.Second)
+	for len(received) < 5 {
+		select {
+		case event := <-events:
+			if event.Task != task {
+				t.Fatalf("Event of the unknown task: %+v.", event)
+			}
+			received[event.Type] = event
+		case <-timeout:
+			t.Fatalf("Events have not been received: %v.", received)
+		}
+	}
+
+	for _, eventType := range []EventType{EventTaskAdded, EventTaskStarted, EventRunStarted, EventRunFailed, EventTaskCompleted} {
+		if _, ok := received[eventType]; !ok {
+			t.Fatalf("Event %s has not been received: %v.", eventType, received)
+		}
+	}
+	if run := received[EventRunStarted].Run; run == nil || run.Status != RunRunning {
+		t.Fatalf("Incorrect run of the started event: %+v.", run)
+	}
+	if run := received[EventRunFailed].Run; run == nil || run.Status != RunFailed || !errors.Is(run.Err, failure) {
+		t.Fatalf("Incorrect run of the failed event: %+v.", run)
+	}
+}
+
+// TestScheduler_Subscribe tests that subscription channel receives events and
+// it is closed once the subscription is cancelled.
+func TestScheduler_Subscribe(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+	events, cancel := newScheduler.Subscribe(8)
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+	handle, _ := newScheduler.TriggerTask(task)
+	<-handle.Done()
+	_ = newScheduler.StopTask(task)
+
+	expected := []EventType{EventTaskAdded, EventRunStarted, EventRunSucceeded, EventTaskRemoved}
+	received := make(map[EventType]bool)
+	timeout := time.After(time.Second)
+	for len(received) < len(expected) {
+		select {
+		case event := <-events:
+			received[event.Type] = true
+		case <-timeout:
+			t.Fatalf("Events have not been received: %v.", received)
+		}
+	}
+	for _, eventType := range expected {
+		if !received[eventType] {
+			t.Fatalf("Event %s has not been received: %v.", eventType, received)
+		}
+	}
+
+	cancel()
+	for range events {
+	}
+	cancel()
+}
+
+// TestScheduler_Subscribe_Skipped tests that skipped runs are published with
+// the reason.
+func TestScheduler_Subscribe_Skipped(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+	events, cancel := newScheduler.Subscribe(8)
+	defer cancel()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	task := newScheduler.ScheduleTask("Task", nil, nil, time.Hour, func(task *Task) {
+		close(started)
+		<-release
+	})
+	<-started
+	_, _ = newScheduler.TriggerTask(task)
+	close(release)
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == EventRunSkipped {
+				if event.Run == nil || !errors.Is(event.Run.Err, ErrTaskRunning) || !event.Run.Triggered {
+					t.Fatalf("Incorrect skipped run: %+v.", event.Run)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Skipped event has not been received.")
+		}
+	}
+}
+
+// TestScheduler_DroppedEvents tests that events not fitting into the buffer of
+// the subscription are counted.
+func TestScheduler_DroppedEvents(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+	events, cancel := newScheduler.Subscribe(1)
+	defer cancel()
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+	_ = newScheduler.StopTask(task)
+
+	if dropped := newScheduler.DroppedEvents(); dropped != 1 {
+		t.Fatalf("Incorrect number of dropped events. Expected: 1. Actual: %d.", dropped)
+	}
+	if event := <-events; event.Type != EventTaskAdded {
+		t.Fatalf("Incorrect buffered event: %+v.", event)
+	}
+}
+
+// TestScheduler_Shutdown_Listeners tests that listeners receive the final
+// events and their goroutines exit once the scheduler is shut down.
+func TestScheduler_Shutdown_Listeners(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	received := make(chan EventType, 16)
+	newScheduler := New(WithListener(ListenerFunc(func(event Event) {
+		received <- event.Type
+	})))
+	events, _ := newScheduler.Subscribe(8)
+
+	start := time.Now().Add(time.Hour)
+	_ = newScheduler.Schedule(NewTask("", "Task", &start, nil, time.Hour, nil, nil), func(task *Task) {})
+	_ = newScheduler.Shutdown(context.Background())
+
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > goroutines; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Goroutines have leaked after shutdown. Expected: %d. Actual: %d.", goroutines, runtime.NumGoroutine())
+		}
+	}
+	for range events {
+	}
+	if first, last := <-received, <-received; first != EventTaskAdded || last != EventTaskRemoved {
+		t.Fatalf("Final events have not been delivered: %s, %s.", first, last)
+	}
+	newScheduler.AddListener(ListenerFunc(func(event Event) {}))
+	if len(newScheduler.events.subscriptions) != 0 {
+		t.Fatalf("Listener has been added after shutdown.")
+	}
+}
+
+// TestTask_Missed tests that missed fire times are counted for intervals and
+// cron expressions.
+func TestTask_Missed(t *testing.T) {
+	fired := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	task := &Task{Interval: time.Minute}
+	if missed := task.missed(fired, fired.Add(time.Minute)); missed != 0 {
+		t.Fatalf("Incorrect missed count of the next fire time: %d.", missed)
+	}
+	if missed := task.missed(fired, fired.Add(5*time.Minute)); missed != 4 {
+		t.Fatalf("Incorrect missed count of the interval: %d.", missed)
+	}
+
+	task = &Task{Cron: "0 * * * *"}
+	_ = task.parseCron()
+	if missed := task.missed(fired, fired.Add(3*time.Hour)); missed != 2 {
+		t.Fatalf("Incorrect missed count of the cron expression: %d.", missed)
+	}
+	if missed := task.missed(fired, time.Time{}); missed != 0 {
+		t.Fatalf("Incorrect missed count of the completed task: %d.", missed)
+	}
+}
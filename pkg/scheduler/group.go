@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrGroupLimit is recorded as the reason of the skipped run, when the
+// concurrency limit of the task group is reached and GroupSkip policy is used.
+var ErrGroupLimit = errors.New("concurrency limit of the task group is reached")
+
+// GroupPolicy defines what happens with the run when the concurrency limit of
+// its task group is reached.
+type GroupPolicy int
+
+const (
+	// GroupWait holds the run until a slot in the group is free, the worker is
+	// not occupied meanwhile. It is the default policy.
+	GroupWait GroupPolicy = iota
+	// GroupSkip skips the run and records it as skipped in the task history with
+	// ErrGroupLimit.
+	GroupSkip
+)
+
+// GroupStats stores the state and counters of the task group.
+type GroupStats struct {
+	// Name is the name of the group.
+	Name string `json:"name"`
+	// Limit is the maximum number of runs executed at the same time, no limit if
+	// it is not positive.
+	Limit int `json:"limit"`
+	// Policy defines what happens with the run when the limit is reached.
+	Policy GroupPolicy `json:"policy"`
+	// Running is the number of runs holding a slot, they are executed or queued
+	// for a free worker.
+	Running int `json:"running"`
+	// Waiting is the number of runs held until a slot is free.
+	Waiting int `json:"waiting"`
+	// Executed counts runs that have been started.
+	Executed int `json:"executed"`
+	// Held counts runs that have waited for a free slot.
+	Held int `json:"held"`
+	// Skipped counts runs skipped because the limit was reached.
+	Skipped int `json:"skipped"`
+	// WaitTime is the total time runs have waited for a free slot.
+	WaitTime time.Duration `json:"wait_time"`
+}
+
+// taskGroup is a named semaphore shared by tasks with the same Task.Group.
+type taskGroup struct {
+	// stats stores configuration and counters of the group.
+	stats GroupStats
+	// waiting stores runs held until a slot is free ordered by priority.
+	waiting dispatchQueue
+}
+
+// WithGroup sets the concurrency limit and policy of the task group with
+// provided name, see Scheduler.SetGroupLimit.
+func WithGroup(name string, limit int, policy GroupPolicy) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.setGroupLimit(name, limit, policy)
+	}
+}
+
+// SetGroupLimit sets how many runs of the tasks that joined the group with
+// provided name using Task.Group could be executed at the same time and what
+// happens with the run when the limit is reached. Limit that is not positive
+// removes the restriction. Runs that are already executing are not affected by
+// the lower limit.
+func (scheduler *Scheduler) SetGroupLimit(name string, limit int, policy GroupPolicy) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	scheduler.setGroupLimit(name, limit, policy)
+}
+
+// setGroupLimit configures the group and resumes held runs if the limit has
+// been increased. It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) setGroupLimit(name string, limit int, policy GroupPolicy) {
+	group := scheduler.group(name)
+	group.stats.Limit = limit
+	group.stats.Policy = policy
+	scheduler.resumeGroup(group)
+}
+
+// GroupStats returns the state and counters of the group with provided name, it
+// returns false if no limit has been set for the group and none of the tasks
+// has joined it yet.
+func (scheduler *Scheduler) GroupStats(name string) (GroupStats, bool) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	group, ok := scheduler.groups[name]
+	if !ok {
+		return GroupStats{}, false
+	}
+	return group.snapshot(), true
+}
+
+// Groups returns the state and counters of all task groups ordered by name.
+func (scheduler *Scheduler) Groups() []GroupStats {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	groups := make([]GroupStats, 0, len(scheduler.groups))
+	for _, group := range scheduler.groups {
+		groups = append(groups, group.snapshot())
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// snapshot returns a copy of the group stats.
+func (group *taskGroup) snapshot() GroupStats {
+	stats := group.stats
+	stats.Waiting = len(group.waiting)
+	return stats
+}
+
+// group returns the group with provided name, it is created if it doesn't exist.
+// It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) group(name string) *taskGroup {
+	if scheduler.groups == nil {
+		scheduler.groups = make(map[string]*taskGroup)
+	}
+	group, ok := scheduler.groups[name]
+	if !ok {
+		group = &taskGroup{stats: GroupStats{Name: name}}
+		scheduler.groups[name] = group
+	}
+	return group
+}
+
+// full checks whether all slots of the group are taken.
+func (group *taskGroup) full() bool {
+	return group.stats.Limit > 0 && group.stats.Running >= group.stats.Limit
+}
+
+// acquire takes a slot in the group of the run. It returns false if the run
+// shall not be executed now, because it has been held or skipped. It shall be
+// called with the scheduler mutex locked.
+func (scheduler *Scheduler) acquire(item *dispatch, now time.Time) bool {
+	if item.task.Group == "" || item.group != nil {
+		return true
+	}
+
+	group := scheduler.group(item.task.Group)
+	if group.full() {
+		if group.stats.Policy == GroupSkip {
+			group.stats.Skipped++
+			scheduler.skipDispatch(*item, ErrGroupLimit)
+			scheduler.release(item.task)
+			return false
+		}
+		group.stats.Held++
+		item.held = now
+		heap.Push(&group.waiting, *item)
+		return false
+	}
+
+	group.take(item)
+	return true
+}
+
+// take reserves a slot in the group for the run.
+func (group *taskGroup) take(item *dispatch) {
+	group.stats.Running++
+	group.stats.Executed++
+	item.group = group
+}
+
+// releaseGroup frees the slot taken by the run and resumes held runs. It shall
+// be called with the scheduler mutex locked.
+func (scheduler *Scheduler) releaseGroup(item dispatch) {
+	if item.group == nil {
+		return
+	}
+	item.group.stats.Running--
+	scheduler.resumeGroup(item.group)
+}
+
+// resumeGroup reserves free slots of the group for the held runs and puts them
+// back to the queue. It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) resumeGroup(group *taskGroup) {
+	now := time.Now()
+	for len(group.waiting) > 0 && !group.full() {
+		item := heap.Pop(&group.waiting).(dispatch)
+		group.stats.WaitTime += now.Sub(item.held)
+		group.take(&item)
+		heap.Push(&scheduler.queue, item)
+		scheduler.work.Signal()
+	}
+}
+
+// dropHeld skips runs held by all groups. It shall be called with the scheduler
+// mutex locked.
+func (scheduler *Scheduler) dropHeld(reason error) {
+	for _, group := range scheduler.groups {
+		for _, item := range group.waiting {
+			scheduler.skipDispatch(item, reason)
+			scheduler.release(item.task)
+		}
+		group.waiting = nil
+	}
+}
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestScheduler_GroupLimit tests that tasks in the same group are executed at
+// most by the group limit at the same time, while other tasks are not affected.
+func TestScheduler_GroupLimit(t *testing.T) {
+	counter := &ConcurrencyCounter{}
+	newScheduler := New(WithWorkers(8), WithGroup("export", 2, GroupWait))
+	defer newScheduler.Shutdown(context.Background())
+
+	tasks := make([]*Task, 0)
+	for index := 0; index < 5; index++ {
+		task := NewTask("", "Export", nil, nil, 0, nil, nil)
+		task.Group = "export"
+		_ = newScheduler.Schedule(task, func(task *Task) {
+			counter.Run(50 * time.Millisecond)
+		})
+		tasks = append(tasks, task)
+	}
+
+	independent := newScheduler.ScheduleTask("Independent", nil, nil, 0, func(task *Task) {})
+	independent.Wait()
+	if stats, _ := newScheduler.GroupStats("export"); stats.Waiting == 0 {
+		t.Fatalf("Runs have not been held by the group: %+v.", stats)
+	}
+
+	for _, task := range tasks {
+		task.Wait()
+	}
+
+	if counter.Maximum() != 2 {
+		t.Fatalf("Incorrect number of concurrent runs. Expected: 2. Actual: %d.", counter.Maximum())
+	}
+
+	stats, ok := newScheduler.GroupStats("export")
+	if !ok {
+		t.Fatalf("Group stats have not been found.")
+	}
+	if stats.Executed != 5 || stats.Held != 3 || stats.Running != 0 || stats.Waiting != 0 || stats.WaitTime <= 0 {
+		t.Fatalf("Incorrect group stats: %+v.", stats)
+	}
+}
+
+// TestScheduler_GroupSkip tests that runs are skipped when the group limit is
+// reached and GroupSkip policy is used.
+func TestScheduler_GroupSkip(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+	newScheduler.SetGroupLimit("export", 1, GroupSkip)
+
+	release := make(chan struct{})
+	tasks := make([]*Task, 0)
+	for index := 0; index < 3; index++ {
+		task := NewTask("", "Export", nil, nil, 0, nil, nil)
+		task.Group = "export"
+		_ = newScheduler.Schedule(task, func(task *Task) {
+			<-release
+		})
+		tasks = append(tasks, task)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	succeeded, skipped := 0, 0
+	for _, task := range tasks {
+		task.Wait()
+		run, _ := task.LastRun()
+		switch {
+		case run.Status == RunSucceeded:
+			succeeded++
+		case run.Status == RunSkipped && errors.Is(run.Err, ErrGroupLimit):
+			skipped++
+		}
+	}
+
+	if succeeded != 1 || skipped != 2 {
+		t.Fatalf("Incorrect outcome of the runs. Succeeded: %d. Skipped: %d.", succeeded, skipped)
+	}
+
+	groups := newScheduler.Groups()
+	if len(groups) != 1 || groups[0].Name != "export" || groups[0].Skipped != 2 {
+		t.Fatalf("Incorrect groups: %+v.", groups)
+	}
+}
+
+// TestScheduler_SetGroupLimit tests that held runs are resumed when the group
+// limit is increased and dropped on shutdown.
+func TestScheduler_SetGroupLimit(t *testing.T) {
+	newScheduler := New(WithGroup("export", 1, GroupWait))
+
+	release := make(chan struct{})
+	tasks := make([]*Task, 0)
+	for index := 0; index < 4; index++ {
+		task := NewTask("", "Export", nil, nil, 0, nil, nil)
+		task.Group = "export"
+		_ = newScheduler.Schedule(task, func(task *Task) {
+			<-release
+		})
+		tasks = append(tasks, task)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	newScheduler.SetGroupLimit("export", 2, GroupWait)
+	time.Sleep(50 * time.Millisecond)
+
+	if stats, _ := newScheduler.GroupStats("export"); stats.Running != 2 || stats.Waiting != 2 {
+		t.Fatalf("Held run has not been resumed: %+v.", stats)
+	}
+
+	close(release)
+	_ = newScheduler.Shutdown(context.Background())
+
+	for _, task := range tasks {
+		task.Wait()
+	}
+	if stats, _ := newScheduler.GroupStats("export"); stats.Running != 0 || stats.Waiting != 0 {
+		t.Fatalf("Group has not been released on shutdown: %+v.", stats)
+	}
+}
@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthOptions configures checks of the health report, zero value of the
+// option disables the check.
+type HealthOptions struct {
+	// OverdueThreshold is how long the task could stay past its fire time
+	// before it is reported as overdue, e.g. because the timing loop is stuck
+	// or the worker pool is saturated.
+	OverdueThreshold time.Duration
+	// FailureThreshold is the number of the latest runs of the task that have
+	// to fail for the task to be reported as failing.
+	FailureThreshold int
+	// MaxRuntime is how long the run could be executed before it is reported
+	// as stuck.
+	MaxRuntime time.Duration
+}
+
+// TaskHealth describes the task that has not passed the health check.
+type TaskHealth struct {
+	// ID is the ID of the task.
+	ID string `json:"id"`
+	// Name is the name of the task.
+	Name string `json:"name"`
+	// Schedule describes the schedule of the task in plain English.
+	Schedule string `json:"schedule"`
+	// Overdue is how long the task is past its fire time.
+	Overdue time.Duration `json:"overdue,omitempty"`
+	// Failures is the number of the latest failed runs.
+	Failures int `json:"failures,omitempty"`
+	// Run is the number of the stuck run.
+	Run int `json:"run,omitempty"`
+	// Runtime is how long the stuck run is executed.
+	Runtime time.Duration `json:"runtime,omitempty"`
+	// task is described once the report is collected.
+	task *Task
+}
+
+// HealthReport describes the state of the Scheduler.
+type HealthReport struct {
+	// Healthy is set if the scheduler is running and all checks have passed.
+	Healthy bool `json:"healthy"`
+	// Running is set until the scheduler is shut down.
+	Running bool `json:"running"`
+	// Time is when the report has been created.
+	Time time.Time `json:"time"`
+	// Tasks is the number of scheduled tasks.
+	Tasks int `json:"tasks"`
+	// Overdue lists tasks past their fire time beyond the threshold.
+	Overdue []TaskHealth `json:"overdue,omitempty"`
+	// Failing lists tasks whose latest runs have failed.
+	Failing []TaskHealth `json:"failing,omitempty"`
+	// Stuck lists runs executed longer than the maximum runtime.
+	Stuck []TaskHealth `json:"stuck,omitempty"`
+}
+
+// Health returns the health report of the Scheduler.
+func (scheduler *Scheduler) Health(options HealthOptions) HealthReport {
+	report := scheduler.collectHealth(options)
+
+	// Schedules are described after the scheduler mutex is released, only for
+	// tasks listed in the report.
+	schedules := make(map[*Task]string)
+	for _, list := range [][]TaskHealth{report.Overdue, report.Failing, report.Stuck} {
+		for index := range list {
+			task := list[index].task
+			if _, ok := schedules[task]; !ok {
+				schedules[task] = task.Describe()
+			}
+			list[index].Schedule = schedules[task]
+		}
+	}
+	return report
+}
+
+// collectHealth checks all tasks and returns the report without schedule
+// descriptions.
+func (scheduler *Scheduler) collectHealth(options HealthOptions) HealthReport {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	now := time.Now()
+	report := HealthReport{Running: !scheduler.shutdown, Time: now, Tasks: len(scheduler.Tasks)}
+	for _, task := range scheduler.Tasks {
+		if options.OverdueThreshold > 0 && scheduler.hasTimer(task) && now.Sub(task.nextFire) > options.OverdueThreshold {
+			report.Overdue = append(report.Overdue, TaskHealth{ID: task.ID, Name: task.Name, task: task, Overdue: now.Sub(task.nextFire)})
+		}
+		if options.FailureThreshold > 0 {
+			if failures := task.failures(); failures >= options.FailureThreshold {
+				report.Failing = append(report.Failing, TaskHealth{ID: task.ID, Name: task.Name, task: task, Failures: failures})
+			}
+		}
+		if options.MaxRuntime > 0 {
+			report.Stuck = append(report.Stuck, task.stuck(now, options.MaxRuntime)...)
+		}
+	}
+	report.Healthy = report.Running && len(report.Overdue) == 0 && len(report.Failing) == 0 && len(report.Stuck) == 0
+	return report
+}
+
+// failures returns the number of the latest runs that have failed, running
+// and skipped runs are ignored.
+func (task *Task) failures() int {
+	task.mutex.RLock()
+	defer task.mutex.RUnlock()
+
+	failures := 0
+	for index := len(task.history) - 1; index >= 0; index-- {
+		status := task.history[index].Status
+		if status == RunSucceeded {
+			break
+		}
+		if status == RunFailed {
+			failures++
+		}
+	}
+	return failures
+}
+
+// stuck returns runs of the task executed longer than the maximum runtime.
+func (task *Task) stuck(now time.Time, maxRuntime time.Duration) []TaskHealth {
+	task.mutex.RLock()
+	defer task.mutex.RUnlock()
+
+	stuck := make([]TaskHealth, 0)
+	for _, run := range task.history {
+		if run.Status == RunRunning && now.Sub(run.Started) > maxRuntime {
+			stuck = append(stuck, TaskHealth{ID: task.ID, Name: task.Name, task: task, Run: run.Number, Runtime: now.Sub(run.Started)})
+		}
+	}
+	return stuck
+}
+
+// LivenessHandler returns the handler for the liveness probe, it responds with
+// 200 OK while the scheduler is running and no task is overdue, which means
+// that the timing loop is alive, otherwise it responds with 503 Service
+// Unavailable. Only OverdueThreshold of the options is used.
+func (scheduler *Scheduler) LivenessHandler(options HealthOptions) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		report := scheduler.Health(HealthOptions{OverdueThreshold: options.OverdueThreshold})
+		writeHealth(writer, report, report.Running && len(report.Overdue) == 0)
+	})
+}
+
+// ReadinessHandler returns the handler for the readiness probe, it responds
+// with 200 OK if the report is healthy, otherwise it responds with 503
+// Service Unavailable. The report is written as JSON.
+func (scheduler *Scheduler) ReadinessHandler(options HealthOptions) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		report := scheduler.Health(options)
+		writeHealth(writer, report, report.Healthy)
+	})
+}
+
+// writeHealth writes the report as JSON with the status code of the probe.
+func writeHealth(writer http.ResponseWriter, report HealthReport, healthy bool) {
+	writer.Header().Set("Content-Type", "application/json")
+	if healthy {
+		writer.WriteHeader(http.StatusOK)
+	} else {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(writer).Encode(report)
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestScheduler_Health tests that the health report lists tasks whose latest
+// runs have failed and runs executed longer than the maximum runtime.
+func TestScheduler_Health(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	failing := NewTask("", "Failing", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(failing, func(task *Task) error {
+		return errors.New("failure")
+	})
+	for index := 0; index < 3; index++ {
+		handle, _ := newScheduler.TriggerTask(failing)
+		<-handle.Done()
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	stuck := NewTask("", "Stuck", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(stuck, func(task *Task) {
+		close(started)
+		<-release
+	})
+	_, _ = newScheduler.TriggerTask(stuck)
+	<-started
+	time.Sleep(20 * time.Millisecond)
+
+	report := newScheduler.Health(HealthOptions{FailureThreshold: 3, MaxRuntime: 10 * time.Millisecond})
+	if report.Healthy || !report.Running || report.Tasks != 2 {
+		t.Fatalf("Incorrect health report: %+v.", report)
+	}
+	if len(report.Failing) != 1 || report.Failing[0].ID != failing.ID || report.Failing[0].Failures != 3 || report.Failing[0].Schedule != failing.Describe() {
+		t.Fatalf("Failing task has not been reported: %+v.", report.Failing)
+	}
+	if len(report.Stuck) != 1 || report.Stuck[0].ID != stuck.ID || report.Stuck[0].Run != 1 {
+		t.Fatalf("Stuck run has not been reported: %+v.", report.Stuck)
+	}
+	if report = newScheduler.Health(HealthOptions{FailureThreshold: 4}); !report.Healthy {
+		t.Fatalf("Health report is not healthy below the thresholds: %+v.", report)
+	}
+
+	recorder := httptest.NewRecorder()
+	newScheduler.ReadinessHandler(HealthOptions{FailureThreshold: 3}).ServeHTTP(recorder, httptest.NewRequest("GET", "/readyz", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Incorrect status of the readiness probe: %d.", recorder.Code)
+	}
+	recorder = httptest.NewRecorder()
+	newScheduler.LivenessHandler(HealthOptions{FailureThreshold: 3, OverdueThreshold: time.Second}).ServeHTTP(recorder, httptest.NewRequest("GET", "/livez", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Incorrect status of the liveness probe: %d.", recorder.Code)
+	}
+}
+
+// TestScheduler_Health_Overdue tests that tasks past their fire time are
+// reported as overdue and the liveness probe fails after the shutdown.
+func TestScheduler_Health_Overdue(t *testing.T) {
+	newScheduler := New()
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+
+	// Move the fire time to the past without waking the timing loop, as if the
+	// loop was stuck.
+	newScheduler.mutex.Lock()
+	task.nextFire = time.Now().Add(-time.Minute)
+	newScheduler.mutex.Unlock()
+
+	report := newScheduler.Health(HealthOptions{OverdueThreshold: time.Second})
+	if report.Healthy || len(report.Overdue) != 1 || report.Overdue[0].Overdue < time.Minute {
+		t.Fatalf("Overdue task has not been reported: %+v.", report)
+	}
+
+	_ = newScheduler.Shutdown(context.Background())
+	recorder := httptest.NewRecorder()
+	newScheduler.LivenessHandler(HealthOptions{}).ServeHTTP(recorder, httptest.NewRequest("GET", "/livez", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Incorrect status of the liveness probe after the shutdown: %d.", recorder.Code)
+	}
+}
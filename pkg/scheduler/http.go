@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// HTTPRequest is a job that sends HTTP request. Its Run method could be passed
+// directly to the Scheduler.ScheduleTask:
+//
+//	request := scheduler.NewHTTPRequest(http.MethodPost, "https://example.com/hook")
+//	request.Body = `{"task": "{{.Task.Name}}", "run": {{.Run.Number}}}`
+//	newScheduler.ScheduleTask("Webhook", nil, nil, 5*time.Minute, request.Run)
+//
+// Response is stored as HTTPResult in the Run record, response with unexpected
+// status marks execution as failed.
+type HTTPRequest struct {
+	// Method is HTTP method, GET if empty.
+	Method string `json:"method,omitempty"`
+	// URL is the request target.
+	URL string `json:"url"`
+	// Header stores request headers.
+	Header http.Header `json:"header,omitempty"`
+	// Body is a text/template of the request body. It is executed with
+	// HTTPTemplateData of the current execution.
+	Body string `json:"body,omitempty"`
+	// Timeout limits duration of the request, in addition to the cancellation
+	// of the task. No additional limit if zero.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// ExpectedStatus lists accepted response status codes, any 2xx status is
+	// accepted if empty.
+	ExpectedStatus []int `json:"expected_status,omitempty"`
+	// MaxResponse limits how many bytes of the response body are captured,
+	// DefaultMaxOutput is used if it is zero and body is not captured at all if
+	// it is negative.
+	MaxResponse int `json:"max_response,omitempty"`
+	// Client is used to send the request, http.DefaultClient if nil.
+	Client *http.Client `json:"-"`
+}
+
+// HTTPTemplateData is passed to the body template of the HTTPRequest.
+type HTTPTemplateData struct {
+	// Task is the scheduled task, its context is available using
+	// {{.Task.GetFromContext "key"}}.
+	Task *Task
+	// Run is the record of the current execution, nil if request is sent
+	// outside the scheduler.
+	Run *Run
+	// Time is the moment when request is prepared.
+	Time time.Time
+}
+
+// HTTPResult stores outcome of the single HTTPRequest execution.
+type HTTPResult struct {
+	// StatusCode stores response status code.
+	StatusCode int `json:"status_code"`
+	// Header stores response headers.
+	Header http.Header `json:"header,omitempty"`
+	// Body stores captured response body.
+	Body string `json:"body,omitempty"`
+	// Truncated is true if response body exceeded HTTPRequest.MaxResponse.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// NewHTTPRequest creates a new HTTPRequest with provided method and URL.
+func NewHTTPRequest(method string, url string) *HTTPRequest {
+	return &HTTPRequest{Method: method, URL: url}
+}
+
+// Run sends the request once and reads the response. It returns the response
+// and an error if the body template is invalid, request failed or response
+// status is not expected.
+func (request *HTTPRequest) Run(ctx context.Context, task *Task) (*HTTPResult, error) {
+	if request.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, request.Timeout)
+		defer cancel()
+	}
+
+	body, err := request.renderBody(HTTPTemplateData{Task: task, Run: RunFromContext(ctx), Time: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, method, request.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range request.Header {
+		httpRequest.Header[key] = append([]string(nil), values...)
+	}
+
+	client := request.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	limit := request.MaxResponse
+	if limit == 0 {
+		limit = DefaultMaxOutput
+	}
+	responseBody := &limitedBuffer{limit: limit}
+	if _, err = io.Copy(responseBody, response.Body); err != nil {
+		return nil, fmt.Errorf("cannot read response from %s: %w", request.URL, err)
+	}
+
+	result := &HTTPResult{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+		Body:       responseBody.String(),
+		Truncated:  responseBody.truncated,
+	}
+
+	if !request.isExpectedStatus(response.StatusCode) {
+		return result, fmt.Errorf("%s %s returned unexpected status: %s", method, request.URL, response.Status)
+	}
+	return result, nil
+}
+
+// renderBody executes body template, it returns nil reader for the empty body.
+func (request *HTTPRequest) renderBody(data HTTPTemplateData) (io.Reader, error) {
+	if request.Body == "" {
+		return nil, nil
+	}
+
+	bodyTemplate, err := template.New("body").Option("missingkey=error").Parse(request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+
+	body := &strings.Builder{}
+	if err = bodyTemplate.Execute(body, data); err != nil {
+		return nil, fmt.Errorf("cannot render body template: %w", err)
+	}
+	return strings.NewReader(body.String()), nil
+}
+
+// isExpectedStatus checks response status against HTTPRequest.ExpectedStatus,
+// any 2xx status is expected if the list is empty.
+func (request *HTTPRequest) isExpectedStatus(status int) bool {
+	if len(request.ExpectedStatus) == 0 {
+		return status >= http.StatusOK && status < http.StatusMultipleChoices
+	}
+	for _, expected := range request.ExpectedStatus {
+		if expected == status {
+			return true
+		}
+	}
+	return false
+}
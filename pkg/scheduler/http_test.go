@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RunHTTPRequest sends the request within the new run of the task and returns
+// the run record together with the returned error.
+func RunHTTPRequest(request *HTTPRequest, task *Task, ctx context.Context) (Run, error) {
+	err := task.execute(ctx, request.Run)
+	lastRun, _ := task.LastRun()
+	return lastRun, err
+}
+
+// TestHTTPRequest_Run tests that HTTPRequest.Run sends configured method,
+// headers and rendered body, and stores response in the run record.
+func TestHTTPRequest_Run(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		if request.Method != http.MethodPost || request.Header.Get("X-Token") != "secret" || string(body) != "Test Task #1 value" {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writer.Header().Set("X-Result", "ok")
+		writer.WriteHeader(http.StatusCreated)
+		_, _ = writer.Write([]byte("created"))
+	}))
+	defer server.Close()
+
+	task := NewSimpleTask("Test Task", time.Second)
+	task.SetToContext("key", "value")
+
+	request := NewHTTPRequest(http.MethodPost, server.URL)
+	request.Header = http.Header{"X-Token": {"secret"}}
+	request.Body = `{{.Task.Name}} #{{.Run.Number}} {{.Task.GetFromContext "key"}}`
+
+	run, err := RunHTTPRequest(request, task, context.Background())
+	if err != nil {
+		t.Fatalf("Request has failed: %v.", err)
+	}
+
+	result, ok := run.Result.(*HTTPResult)
+	if !ok {
+		t.Fatalf("Run result is not HTTPResult: %v.", run.Result)
+	}
+
+	if result.StatusCode != http.StatusCreated || result.Body != "created" || result.Header.Get("X-Result") != "ok" {
+		t.Fatalf("Incorrect response in the run record: %+v.", result)
+	}
+}
+
+// TestHTTPRequest_Run_UnexpectedStatus tests that HTTPRequest.Run fails when
+// response status is not in HTTPRequest.ExpectedStatus.
+func TestHTTPRequest_Run_UnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	request := NewHTTPRequest(http.MethodGet, server.URL)
+	request.ExpectedStatus = []int{http.StatusNoContent}
+
+	run, err := RunHTTPRequest(request, NewSimpleTask("", time.Second), context.Background())
+	if err == nil || !strings.Contains(err.Error(), "unexpected status") {
+		t.Fatalf("Unexpected status has not been reported: %v.", err)
+	}
+
+	if run.Status != RunFailed || run.Result.(*HTTPResult).StatusCode != http.StatusOK {
+		t.Fatalf("Failed request has been recorded incorrectly: %+v.", run)
+	}
+}
+
+// TestHTTPRequest_Run_Timeout tests that HTTPRequest.Run is interrupted after
+// HTTPRequest.Timeout.
+func TestHTTPRequest_Run_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		select {
+		case <-request.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	request := NewHTTPRequest(http.MethodGet, server.URL)
+	request.Timeout = 100 * time.Millisecond
+
+	started := time.Now()
+	_, err := RunHTTPRequest(request, NewSimpleTask("", time.Second), context.Background())
+	if err == nil || time.Since(started) > 2*time.Second {
+		t.Fatalf("Request has not been interrupted after timeout: %v.", err)
+	}
+}
+
+// TestHTTPRequest_Run_InvalidTemplate tests that HTTPRequest.Run fails without
+// sending request when body template is invalid.
+func TestHTTPRequest_Run_InvalidTemplate(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	request := NewHTTPRequest(http.MethodPost, server.URL)
+	request.Body = "{{.Missing"
+
+	_, err := RunHTTPRequest(request, NewSimpleTask("", time.Second), context.Background())
+	if err == nil || requests != 0 {
+		t.Fatalf("Invalid template has not been reported: %v, requests sent: %d.", err, requests)
+	}
+}
+
+// TestHTTPRequest_Run_Scheduled tests that HTTPRequest.Run could be scheduled
+// directly and every execution sends the request.
+func TestHTTPRequest_Run_Scheduled(t *testing.T) {
+	requests := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		requests <- string(body)
+	}))
+	defer server.Close()
+
+	request := NewHTTPRequest(http.MethodPost, server.URL)
+	request.Body = "run {{.Run.Number}}"
+	duration := 250 * time.Millisecond
+
+	newScheduler := CreateEmptyScheduler()
+	newTask := newScheduler.ScheduleTask("Webhook", nil, &duration, 100*time.Millisecond, request.Run)
+	newTask.Wait()
+	close(requests)
+
+	received := make([]string, 0)
+	for body := range requests {
+		received = append(received, body)
+	}
+
+	if strings.Join(received, ",") != "run 1,run 2,run 3" {
+		t.Fatalf("Incorrect requests received: %v.", received)
+	}
+}
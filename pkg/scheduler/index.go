@@ -0,0 +1,140 @@
+package scheduler
+
+// taskIndex provides constant time lookup of scheduled tasks by pointer, ID and
+// name, so that lookups and removals do not scan the whole Scheduler.Tasks.
+type taskIndex struct {
+	// positions stores position of every indexed task in the Scheduler.Tasks.
+	positions map[*Task]int
+	// byID stores tasks by their ID in the order they have been indexed.
+	byID map[string][]*Task
+	// byName stores tasks by their name in the order they have been indexed.
+	byName map[string][]*Task
+	// valid is set once the index is built, it is reset when the index shall be
+	// built again.
+	valid bool
+	// length stores the number of indexed tasks, it is used to detect that
+	// Scheduler.Tasks has been replaced directly.
+	length int
+}
+
+// tasksIndex returns the index of the Scheduler.Tasks, it builds the index if
+// it is not valid or the length of Scheduler.Tasks differs from the number of
+// indexed tasks. It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) tasksIndex() *taskIndex {
+	index := &scheduler.index
+	if !index.valid || index.length != len(scheduler.Tasks) {
+		index.rebuild(scheduler.Tasks)
+	}
+	return index
+}
+
+// rebuild creates index from scratch for provided tasks.
+func (index *taskIndex) rebuild(tasks []*Task) {
+	index.positions = make(map[*Task]int, len(tasks))
+	index.byID = make(map[string][]*Task, len(tasks))
+	index.byName = make(map[string][]*Task, len(tasks))
+	for position, task := range tasks {
+		if _, ok := index.positions[task]; !ok {
+			index.add(task, position)
+		}
+	}
+	index.valid = true
+	index.length = len(tasks)
+}
+
+// add indexes task stored under provided position of the Scheduler.Tasks.
+func (index *taskIndex) add(task *Task, position int) {
+	index.positions[task] = position
+	index.byID[task.ID] = append(index.byID[task.ID], task)
+	index.byName[task.Name] = append(index.byName[task.Name], task)
+}
+
+// position returns position of the task in provided Scheduler.Tasks. The
+// index is invalidated if the task is not at the indexed position anymore.
+func (index *taskIndex) position(tasks []*Task, task *Task) (int, bool) {
+	position, ok := index.positions[task]
+	if !ok {
+		return -1, false
+	}
+	if position >= len(tasks) || tasks[position] != task {
+		index.valid = false
+		return -1, false
+	}
+	return position, true
+}
+
+// taskPosition returns position of the task in the Scheduler.Tasks, the index
+// is built again if the task is not at the indexed position anymore. It shall
+// be called with the scheduler mutex locked.
+func (scheduler *Scheduler) taskPosition(task *Task) (int, bool) {
+	position, ok := scheduler.tasksIndex().position(scheduler.Tasks, task)
+	if !ok && !scheduler.index.valid {
+		position, ok = scheduler.tasksIndex().position(scheduler.Tasks, task)
+	}
+	return position, ok
+}
+
+// addTask appends task to the Scheduler.Tasks and the index. It shall be called
+// with the scheduler mutex locked.
+func (scheduler *Scheduler) addTask(task *Task) {
+	index := scheduler.tasksIndex()
+	scheduler.Tasks = append(scheduler.Tasks, task)
+	index.add(task, len(scheduler.Tasks)-1)
+	index.length = len(scheduler.Tasks)
+}
+
+// deleteTask removes task from the Scheduler.Tasks and the index, the last task
+// takes its place. It returns false if task is not in the Scheduler.Tasks. It
+// shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) deleteTask(task *Task) bool {
+	position, ok := scheduler.taskPosition(task)
+	if !ok {
+		return false
+	}
+	index := &scheduler.index
+
+	last := len(scheduler.Tasks) - 1
+	if position != last {
+		moved := scheduler.Tasks[last]
+		scheduler.Tasks[position] = moved
+		index.positions[moved] = position
+	}
+	scheduler.Tasks[last] = nil
+	scheduler.Tasks = scheduler.Tasks[:last]
+
+	delete(index.positions, task)
+	removeIndexed(index.byID, task.ID, task)
+	removeIndexed(index.byName, task.Name, task)
+	index.length = len(scheduler.Tasks)
+	return true
+}
+
+// removeIndexed removes task from the list stored under provided key, the
+// order of the remaining tasks is kept, so the first of them is the one that
+// has been indexed first.
+func removeIndexed(lists map[string][]*Task, key string, task *Task) {
+	list := lists[key]
+	for position, item := range list {
+		if item != task {
+			continue
+		}
+		last := len(list) - 1
+		copy(list[position:], list[position+1:])
+		list[last] = nil
+		list = list[:last]
+		break
+	}
+	if len(list) == 0 {
+		delete(lists, key)
+		return
+	}
+	lists[key] = list
+}
+
+// firstIndexed returns the first task stored under provided key or nil.
+func firstIndexed(lists map[string][]*Task, key string) *Task {
+	if list := lists[key]; len(list) > 0 {
+		return list[0]
+	}
+	return nil
+}
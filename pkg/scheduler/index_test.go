@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// BenchmarkTasks is the number of tasks scheduled by benchmarks.
+const BenchmarkTasks = 100000
+
+// ScheduleIdleTasks schedules provided number of tasks that fire once in an
+// hour.
+func ScheduleIdleTasks(tb testing.TB, newScheduler *Scheduler, count int) []*Task {
+	start := time.Now().Add(time.Hour)
+	tasks := make([]*Task, count)
+	for index := range tasks {
+		tasks[index] = NewTask("", fmt.Sprintf("Task %d", index), &start, nil, time.Hour, nil, nil)
+		if err := newScheduler.Schedule(tasks[index], func(task *Task) {}); err != nil {
+			tb.Fatalf("Task has not been scheduled. Error: %v.", err)
+		}
+	}
+	return tasks
+}
+
+// TestScheduler_Index tests that lookups by pointer, ID and name stay correct
+// after tasks are removed from the middle of the Scheduler.Tasks.
+func TestScheduler_Index(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	tasks := ScheduleIdleTasks(t, newScheduler, 10)
+	for index := 0; index < len(tasks); index += 3 {
+		if err := newScheduler.StopTask(tasks[index]); err != nil {
+			t.Fatalf("Task has not been stopped. Error: %v.", err)
+		}
+	}
+
+	if len(newScheduler.Tasks) != 6 {
+		t.Fatalf("Incorrect number of scheduled tasks. Expected: %d. Actual: %d.", 6, len(newScheduler.Tasks))
+	}
+	for index, task := range tasks {
+		stopped := index%3 == 0
+		found := newScheduler.FindTaskByID(task.ID)
+		if stopped != (found == nil) {
+			t.Fatalf("Incorrect lookup by ID of task %d. Expected stopped: %v. Actual: %v.", index, stopped, found)
+		}
+		found = newScheduler.FindTaskByName(task.Name)
+		if stopped != (found == nil) {
+			t.Fatalf("Incorrect lookup by name of task %d. Expected stopped: %v. Actual: %v.", index, stopped, found)
+		}
+		position := newScheduler.FindTaskIndex(task)
+		if stopped && position != -1 || !stopped && newScheduler.Tasks[position] != task {
+			t.Fatalf("Incorrect index of task %d: %d.", index, position)
+		}
+	}
+}
+
+// TestScheduler_Index_Tasks tests that lookups follow the Scheduler.Tasks when
+// it is replaced directly.
+func TestScheduler_Index_Tasks(t *testing.T) {
+	newScheduler := CreateSchedulerWithTasks()
+	task := newScheduler.Tasks[0]
+	if newScheduler.FindTaskByID(task.ID) != task {
+		t.Fatalf("Task with ID: \"%s\" was not found.", task.ID)
+	}
+
+	newScheduler.Tasks = newScheduler.Tasks[1:]
+	if found := newScheduler.FindTaskByID(task.ID); found != nil {
+		t.Fatalf("Found removed Task: %v, using FindTaskByID.", found)
+	}
+}
+
+// TestScheduler_Index_Validity tests that the index stays valid after the
+// Scheduler.Tasks becomes empty and it follows tasks moved directly.
+func TestScheduler_Index_Validity(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	for _, task := range ScheduleIdleTasks(t, newScheduler, 3) {
+		_ = newScheduler.StopTask(task)
+	}
+	tasks := ScheduleIdleTasks(t, newScheduler, 2)
+	if newScheduler.FindTaskByID(tasks[0].ID) != tasks[0] || newScheduler.FindTaskIndex(tasks[1]) != 1 {
+		t.Fatalf("Tasks scheduled after the list became empty have not been indexed.")
+	}
+
+	newScheduler.mutex.Lock()
+	newScheduler.Tasks[0], newScheduler.Tasks[1] = newScheduler.Tasks[1], newScheduler.Tasks[0]
+	newScheduler.mutex.Unlock()
+	if position := newScheduler.FindTaskIndex(tasks[0]); position != 1 {
+		t.Fatalf("Index doesn't follow the moved task. Expected: 1. Actual: %d.", position)
+	}
+}
+
+// TestScheduler_FindTaskByName_Duplicates tests that Scheduler.FindTaskByName
+// returns the task scheduled first among the tasks with the same name, also
+// after other tasks are removed.
+func TestScheduler_FindTaskByName_Duplicates(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	tasks := make([]*Task, 4)
+	for index := range tasks {
+		name := "Duplicate"
+		if index == 1 {
+			name = "Other"
+		}
+		tasks[index] = NewTask("", name, &start, nil, time.Hour, nil, nil)
+		_ = newScheduler.Schedule(tasks[index], func(task *Task) {})
+	}
+
+	for _, step := range []struct {
+		stopped  *Task
+		expected *Task
+	}{{nil, tasks[0]}, {tasks[1], tasks[0]}, {tasks[0], tasks[2]}, {tasks[2], tasks[3]}} {
+		if step.stopped != nil {
+			_ = newScheduler.StopTask(step.stopped)
+		}
+		if found := newScheduler.FindTaskByName("Duplicate"); found != step.expected {
+			t.Fatalf("Incorrect task with duplicated name. Expected: %s. Actual: %v.", step.expected.ID, found)
+		}
+	}
+}
+
+// BenchmarkScheduler_Schedule measures scheduling of tasks into the scheduler
+// that already has BenchmarkTasks tasks.
+func BenchmarkScheduler_Schedule(b *testing.B) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+	ScheduleIdleTasks(b, newScheduler, BenchmarkTasks)
+
+	b.ResetTimer()
+	ScheduleIdleTasks(b, newScheduler, b.N)
+}
+
+// BenchmarkScheduler_FindTaskByID measures lookup of the task by ID among
+// BenchmarkTasks tasks.
+func BenchmarkScheduler_FindTaskByID(b *testing.B) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+	tasks := ScheduleIdleTasks(b, newScheduler, BenchmarkTasks)
+
+	b.ResetTimer()
+	for index := 0; index < b.N; index++ {
+		if newScheduler.FindTaskByID(tasks[index%len(tasks)].ID) == nil {
+			b.Fatalf("Task has not been found.")
+		}
+	}
+}
+
+// BenchmarkScheduler_StopTask measures removal of tasks from the scheduler with
+// BenchmarkTasks tasks.
+func BenchmarkScheduler_StopTask(b *testing.B) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+	ScheduleIdleTasks(b, newScheduler, BenchmarkTasks)
+	tasks := ScheduleIdleTasks(b, newScheduler, b.N)
+
+	b.ResetTimer()
+	for _, task := range tasks {
+		if err := newScheduler.StopTask(task); err != nil {
+			b.Fatalf("Task has not been stopped. Error: %v.", err)
+		}
+	}
+}
+
+// BenchmarkScheduler_Fire measures how fast BenchmarkTasks tasks that are due
+// at the same time are fired and executed.
+func BenchmarkScheduler_Fire(b *testing.B) {
+	for iteration := 0; iteration < b.N; iteration++ {
+		b.StopTimer()
+		newScheduler := New()
+		start := time.Now().Add(100 * time.Millisecond)
+		tasks := make([]*Task, BenchmarkTasks)
+		for index := range tasks {
+			tasks[index] = NewTask("", "", &start, nil, 0, nil, nil)
+			if err := newScheduler.Schedule(tasks[index], func(task *Task) {}); err != nil {
+				b.Fatalf("Task has not been scheduled. Error: %v.", err)
+			}
+		}
+		time.Sleep(time.Until(start))
+		b.StartTimer()
+
+		for _, task := range tasks {
+			task.Wait()
+		}
+
+		b.StopTimer()
+		_ = newScheduler.Shutdown(context.Background())
+	}
+	b.ReportMetric(float64(BenchmarkTasks*b.N)/b.Elapsed().Seconds(), "runs/s")
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"time"
+)
+
+// day is the interval that is advanced in calendar days when the task has a
+// Location.
+const day = 24 * time.Hour
+
+// inLocation returns the time in the Location of the task, the time is not
+// changed if the task has no Location.
+func (task *Task) inLocation(moment time.Time) time.Time {
+	if task.Location == nil {
+		return moment
+	}
+	return moment.In(task.Location)
+}
+
+// intervalDays returns the number of calendar days between fire times if the
+// task has a Location and the Interval is a whole number of days, zero
+// otherwise.
+func (task *Task) intervalDays() int {
+	if task.Location == nil || task.Interval <= 0 || task.Interval%day != 0 {
+		return 0
+	}
+	return int(task.Interval / day)
+}
+
+// nextDay returns the first fire time after the planned one that is not in the
+// past, fire times are every intervalDays days at the local time of day of the
+// start time, so they don't drift across daylight saving time transitions.
+func (task *Task) nextDay(planned time.Time, now time.Time) time.Time {
+	days := task.intervalDays()
+	clock := planned
+	if task.Start != nil {
+		clock = task.inLocation(*task.Start)
+	}
+	year, month, date := planned.Date()
+	hour, minute, second := clock.Clock()
+	fireTime := func(offset int) time.Time {
+		return localTime(time.Date(year, month, date+offset, hour, minute, second, clock.Nanosecond(), time.UTC), task.Location)
+	}
+
+	offset := days
+	if next := fireTime(offset); next.Before(now) {
+		// The estimate could be off by one fire time around the transitions.
+		offset = days * (int(now.Sub(planned)/task.Interval) + 1)
+		for fireTime(offset).Before(now) {
+			offset += days
+		}
+		for offset > days && !fireTime(offset-days).Before(now) {
+			offset -= days
+		}
+	}
+	return fireTime(offset)
+}
+
+// wallClock returns the local date and time of day of provided time as UTC,
+// so it could be advanced without daylight saving time transitions.
+func wallClock(moment time.Time) time.Time {
+	year, month, date := moment.Date()
+	hour, minute, second := moment.Clock()
+	return time.Date(year, month, date, hour, minute, second, moment.Nanosecond(), time.UTC)
+}
+
+// localTime returns the time with the date and time of day of the wall clock
+// in the location. A local time repeated by the daylight saving time
+// transition is resolved to its first occurrence, and a local time skipped by
+// the transition is moved forward by the length of the gap.
+func localTime(wall time.Time, location *time.Location) time.Time {
+	moment := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), location)
+
+	// time.Date does not guarantee which occurrence or which offset is used,
+	// so offsets around the time are tried explicitly.
+	result := time.Time{}
+	for _, probe := range []time.Time{moment.Add(-12 * time.Hour), moment, moment.Add(12 * time.Hour)} {
+		_, offset := probe.Zone()
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(location)
+		if wallClock(candidate).Equal(wall) && (result.IsZero() || candidate.Before(result)) {
+			result = candidate
+		}
+	}
+	if result.IsZero() {
+		_, offset := moment.Add(-12 * time.Hour).Zone()
+		result = wall.Add(-time.Duration(offset) * time.Second).In(location)
+	}
+	return result
+}
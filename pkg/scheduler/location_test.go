@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// loadLocation loads the location or fails the test.
+func loadLocation(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("Location %s has not been loaded. Error: %v.", name, err)
+	}
+	return location
+}
+
+// TestLocalTime tests that skipped local times are moved forward by the gap
+// and repeated local times are resolved to their first occurrence.
+func TestLocalTime(t *testing.T) {
+	berlin := loadLocation(t, "Europe/Berlin")
+	newYork := loadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name     string
+		wall     time.Time
+		location *time.Location
+		expected time.Time
+	}{
+		{"Regular", time.Date(2024, time.March, 30, 2, 30, 0, 0, time.UTC), berlin, time.Date(2024, time.March, 30, 1, 30, 0, 0, time.UTC)},
+		{"Skipped Berlin", time.Date(2024, time.March, 31, 2, 30, 0, 0, time.UTC), berlin, time.Date(2024, time.March, 31, 1, 30, 0, 0, time.UTC)},
+		{"Repeated Berlin", time.Date(2024, time.October, 27, 2, 30, 0, 0, time.UTC), berlin, time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC)},
+		{"Skipped New York", time.Date(2024, time.March, 10, 2, 30, 0, 0, time.UTC), newYork, time.Date(2024, time.March, 10, 7, 30, 0, 0, time.UTC)},
+		{"Repeated New York", time.Date(2024, time.November, 3, 1, 30, 0, 0, time.UTC), newYork, time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := localTime(test.wall, test.location)
+			if !actual.Equal(test.expected) || actual.Location() != test.location {
+				t.Fatalf("Incorrect local time: %s, expected: %s.", actual, test.expected.In(test.location))
+			}
+		})
+	}
+}
+
+// TestCronSchedule_Next_DST tests the fire times of cron expressions during
+// daylight saving time transitions.
+func TestCronSchedule_Next_DST(t *testing.T) {
+	berlin := loadLocation(t, "Europe/Berlin")
+	local := func(month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name     string
+		spec     string
+		after    time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "Skipped daily",
+			spec:     "30 2 * * *",
+			after:    local(time.March, 30, 2, 30),
+			expected: []time.Time{local(time.March, 31, 3, 30), local(time.April, 1, 2, 30)},
+		},
+		{
+			name:     "Skipped time matched anyway",
+			spec:     "30 2,3 * * *",
+			after:    local(time.March, 31, 1, 0),
+			expected: []time.Time{local(time.March, 31, 3, 30), local(time.April, 1, 2, 30)},
+		},
+		{
+			name:     "Skipped hourly",
+			spec:     "30 * * * *",
+			after:    local(time.March, 31, 1, 0),
+			expected: []time.Time{local(time.March, 31, 1, 30), local(time.March, 31, 3, 30)},
+		},
+		{
+			name:     "Repeated daily",
+			spec:     "30 2 * * *",
+			after:    local(time.October, 26, 2, 30),
+			expected: []time.Time{local(time.October, 27, 0, 30).Add(2 * time.Hour), local(time.October, 28, 2, 30)},
+		},
+		{
+			name:  "Repeated hourly",
+			spec:  "30 * * * *",
+			after: local(time.October, 27, 1, 0),
+			expected: []time.Time{
+				local(time.October, 27, 1, 30),
+				local(time.October, 27, 1, 30).Add(time.Hour),
+				local(time.October, 27, 1, 30).Add(2 * time.Hour),
+				local(time.October, 27, 3, 30),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, _ := ParseCron(test.spec)
+			current := test.after
+			for _, expected := range test.expected {
+				current = schedule.Next(current)
+				if !current.Equal(expected) {
+					t.Fatalf("Incorrect fire time: %s, expected: %s.", current, expected)
+				}
+			}
+		})
+	}
+}
+
+// TestTask_Location tests that the cron expression is evaluated in the
+// location of the task and the daily interval keeps its local time of day.
+func TestTask_Location(t *testing.T) {
+	berlin := loadLocation(t, "Europe/Berlin")
+	start := time.Date(2024, time.March, 30, 8, 0, 0, 0, time.UTC)
+
+	task := &Task{Start: &start, Interval: 24 * time.Hour, Location: berlin}
+	first := task.firstFireTime(start)
+	if first.Location() != berlin || first.Hour() != 9 {
+		t.Fatalf("First fire time is not in the location: %s.", first)
+	}
+	next := task.nextPlanned(first, time.Time{})
+	if !next.Equal(time.Date(2024, time.March, 31, 9, 0, 0, 0, berlin)) {
+		t.Fatalf("Daily interval has drifted across the transition: %s.", next)
+	}
+	now := time.Date(2024, time.April, 2, 8, 0, 0, 0, berlin)
+	if next = task.nextPlanned(first, now); !next.Equal(time.Date(2024, time.April, 2, 9, 0, 0, 0, berlin)) {
+		t.Fatalf("Incorrect fire time after missed ones: %s.", next)
+	}
+	if missed := task.missed(first, next); missed != 2 {
+		t.Fatalf("Incorrect missed count: %d.", missed)
+	}
+
+	task.Location = nil
+	if next = task.nextPlanned(first, time.Time{}); !next.Equal(first.Add(24 * time.Hour)) {
+		t.Fatalf("Interval without the location is not the elapsed time: %s.", next)
+	}
+
+	newYork := loadLocation(t, "America/New_York")
+	task = &Task{Cron: "0 9 * * *", Location: newYork}
+	_ = task.parseCron()
+	if first = task.firstFireTime(start); !first.Equal(time.Date(2024, time.March, 30, 9, 0, 0, 0, newYork)) {
+		t.Fatalf("Cron expression has not been evaluated in the location: %s.", first)
+	}
+}
+
+// TestScheduler_UpdateLocation tests that the next fire time is calculated in
+// the new location.
+func TestScheduler_UpdateLocation(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	task := NewSimpleTask("Task", time.Hour)
+	task.Cron = "0 9 * * *"
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+	if err := newScheduler.UpdateTask(task, UpdateLocation(tokyo)); err != nil {
+		t.Fatalf("Location has not been updated. Error: %v.", err)
+	}
+
+	newScheduler.mutex.Lock()
+	planned := task.planned
+	newScheduler.mutex.Unlock()
+	if planned.Location() != tokyo || planned.Hour() != 9 {
+		t.Fatalf("Task has not been planned in the location: %s.", planned)
+	}
+}
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// defaultLogLevels stores levels used to log events, if other level was not set
+// using WithLogLevel.
+var defaultLogLevels = map[EventType]slog.Level{
+	EventTaskAdded:     slog.LevelInfo,
+	EventTaskRemoved:   slog.LevelInfo,
+	EventTaskStarted:   slog.LevelDebug,
+	EventRunStarted:    slog.LevelDebug,
+	EventRunSucceeded:  slog.LevelInfo,
+	EventRunFailed:     slog.LevelError,
+	EventRunSkipped:    slog.LevelWarn,
+	EventMisfired:      slog.LevelWarn,
+	EventTaskCompleted: slog.LevelInfo,
+}
+
+// loggerContextKey is the key of the run logger in the context of the
+// execution.
+type loggerContextKey struct{}
+
+// WithLogger makes the Scheduler log all events to the logger, see
+// WithLogLevel for the levels.
+func WithLogger(logger *slog.Logger) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.logger = logger
+	}
+}
+
+// WithLogLevel sets the level used to log events of provided type, the
+// defaults are: Debug for task started and run started, Warn for skipped and
+// misfired runs, Error for failed runs and Info for the rest.
+func WithLogLevel(eventType EventType, level slog.Level) Option {
+	return func(scheduler *Scheduler) {
+		if scheduler.logLevels == nil {
+			scheduler.logLevels = make(map[EventType]slog.Level)
+		}
+		scheduler.logLevels[eventType] = level
+	}
+}
+
+// LoggerFromContext returns the logger of the current execution from the
+// context passed to the scheduled function. The logger has the attributes of
+// the task and the run. If the Scheduler has no logger or the context doesn't
+// belong to any execution, slog.Default is returned.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// contextWithLogger returns a copy of the context that carries the logger of
+// the run, if the Scheduler has a logger.
+func (scheduler *Scheduler) contextWithLogger(ctx context.Context, task *Task, run *Run) context.Context {
+	if scheduler == nil || scheduler.logger == nil {
+		return ctx
+	}
+	logger := scheduler.logger.With(slog.String("task_id", task.ID), slog.String("task_name", task.Name), slog.Int("run", run.Number))
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// logEvent writes the event to the logger of the Scheduler, if any.
+func (scheduler *Scheduler) logEvent(event Event) {
+	if scheduler.logger == nil {
+		return
+	}
+	level, ok := scheduler.logLevels[event.Type]
+	if !ok {
+		level = defaultLogLevels[event.Type]
+	}
+	ctx := context.Background()
+	if !scheduler.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attributes := []slog.Attr{
+		slog.String("event", string(event.Type)),
+		slog.String("task_id", event.Task.ID),
+		slog.String("task_name", event.Task.Name),
+	}
+	if run := event.Run; run != nil {
+		attributes = append(attributes, slog.Int("run", run.Number), slog.Time("scheduled", run.Scheduled))
+		if !run.Finished.IsZero() {
+			attributes = append(attributes, slog.Duration("duration", run.Duration()))
+		}
+		if run.Err != nil {
+			attributes = append(attributes, slog.String("error", run.Err.Error()))
+		}
+	}
+	if event.Type == EventMisfired {
+		attributes = append(attributes, slog.Int("missed", event.Missed))
+	}
+	scheduler.logger.LogAttrs(ctx, level, strings.ReplaceAll(string(event.Type), "_", " "), attributes...)
+}
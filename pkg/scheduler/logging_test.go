@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestScheduler_Logger tests that the scheduler logs events with the attributes
+// of the task and the run, using configured levels, and that the scheduled
+// function receives the logger of the run.
+func TestScheduler_Logger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	newScheduler := New(WithLogger(logger), WithLogLevel(EventTaskAdded, slog.LevelDebug))
+	defer newScheduler.Shutdown(context.Background())
+
+	task := newScheduler.ScheduleTask("Task", nil, nil, 0, func(ctx context.Context, task *Task) error {
+		LoggerFromContext(ctx).Info("inside")
+		return errors.New("failure")
+	})
+	task.Wait()
+
+	records := make(map[string]map[string]interface{})
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		record := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Incorrect log line %q. Error: %v.", line, err)
+		}
+		records[record["msg"].(string)] = record
+	}
+
+	if _, ok := records["task added"]; ok {
+		t.Fatalf("Event has been logged below the configured level: %v.", records)
+	}
+	inside, ok := records["inside"]
+	if !ok || inside["task_id"] != task.ID || inside["task_name"] != "Task" || inside["run"] != float64(1) {
+		t.Fatalf("Incorrect log record of the run logger: %v.", inside)
+	}
+	failed, ok := records["run failed"]
+	if !ok || failed["level"] != "ERROR" || failed["task_id"] != task.ID || failed["error"] != "failure" || failed["duration"] == nil {
+		t.Fatalf("Incorrect log record of the failed run: %v.", failed)
+	}
+	if _, ok = records["task completed"]; !ok {
+		t.Fatalf("Completed task has not been logged: %v.", records)
+	}
+}
+
+// TestLoggerFromContext tests that the default logger is returned outside of
+// the execution.
+func TestLoggerFromContext(t *testing.T) {
+	if LoggerFromContext(context.Background()) != slog.Default() {
+		t.Fatalf("Default logger has not been returned.")
+	}
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import "time"
+
+// Metrics receives measurements of the Scheduler, all task measurements are
+// labelled by the task name. Methods are called synchronously, so they shall
+// be fast and safe for concurrent use.
+type Metrics interface {
+	// SetTasks sets the number of tasks registered in the Scheduler.
+	SetTasks(count int)
+	// ObserveRun records the finished or skipped run of the task with its
+	// status and duration, the duration of the skipped run is zero.
+	ObserveRun(name string, status RunStatus, duration time.Duration)
+	// ObserveLag records the scheduling lag of the run, that is the actual
+	// start time minus the planned fire time including the jitter.
+	ObserveLag(name string, lag time.Duration)
+	// AddMisfires records fire times of the task that have been missed.
+	AddMisfires(name string, count int)
+	// SetWorkers records the worker pool state: busy workers, total workers,
+	// queued runs and the queue size.
+	SetWorkers(busy int, workers int, queued int, queueSize int)
+}
+
+// WithMetrics makes the Scheduler report its measurements to the metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.metrics = metrics
+	}
+}
+
+// observeEvent reports the measurements of the event to the metrics, if any.
+// Task events are published with the scheduler mutex locked, so the number of
+// tasks could be read.
+func (scheduler *Scheduler) observeEvent(event Event) {
+	if scheduler.metrics == nil {
+		return
+	}
+	switch event.Type {
+	case EventTaskAdded, EventTaskRemoved, EventTaskCompleted:
+		scheduler.metrics.SetTasks(len(scheduler.Tasks))
+	case EventRunStarted:
+		scheduler.metrics.ObserveLag(event.Task.Name, event.Run.Started.Sub(event.Run.Scheduled))
+	case EventRunSucceeded, EventRunFailed, EventRunSkipped:
+		duration := time.Duration(0)
+		if event.Run.Status != RunSkipped {
+			duration = event.Run.Duration()
+		}
+		scheduler.metrics.ObserveRun(event.Task.Name, event.Run.Status, duration)
+	case EventMisfired:
+		scheduler.metrics.AddMisfires(event.Task.Name, event.Missed)
+	}
+}
+
+// observeWorkers reports the worker pool state to the metrics, if any. It
+// shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) observeWorkers() {
+	if scheduler.metrics == nil {
+		return
+	}
+	scheduler.metrics.SetWorkers(scheduler.busy, scheduler.workers, len(scheduler.queue), scheduler.queueSize)
+}
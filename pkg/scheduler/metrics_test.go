@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestScheduler_Metrics tests that the scheduler reports registered tasks,
+// outcomes of runs, their durations, scheduling lag and the worker pool state.
+func TestScheduler_Metrics(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	newScheduler := New(WithMetrics(metrics), WithWorkers(2))
+	defer newScheduler.Shutdown(context.Background())
+
+	succeeded := newScheduler.ScheduleTask("Succeeded", nil, nil, 0, func(task *Task) {})
+	failed := newScheduler.ScheduleTask("Failed", nil, nil, 0, func(task *Task) error {
+		return errors.New("failure")
+	})
+	succeeded.Wait()
+	failed.Wait()
+
+	buffer := &bytes.Buffer{}
+	if _, err := metrics.WriteTo(buffer); err != nil {
+		t.Fatalf("Metrics have not been written. Error: %v.", err)
+	}
+	output := buffer.String()
+	for _, line := range []string{
+		"scheduler_tasks_registered 0\n",
+		`scheduler_runs_total{task="Succeeded",outcome="succeeded"} 1` + "\n",
+		`scheduler_runs_total{task="Failed",outcome="failed"} 1` + "\n",
+		`scheduler_run_duration_seconds_count{task="Failed"} 1` + "\n",
+		`scheduler_scheduling_lag_seconds_count{task="Succeeded"} 1` + "\n",
+		"scheduler_workers 2\n",
+		"scheduler_workers_busy 0\n",
+	} {
+		if !strings.Contains(output, line) {
+			t.Fatalf("Metrics don't contain %q:\n%s", line, output)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// ErrSkipped marks the error returned by the Job that has not executed the
+// function, the run is recorded as skipped instead of failed.
+var ErrSkipped = errors.New("run has been skipped")
+
+// ErrPanicked is wrapped by PanicError.
+var ErrPanicked = errors.New("function panicked")
+
+// PanicError is returned by RecoveryMiddleware when the function panics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine at the time of panic.
+	Stack []byte
+}
+
+// Error returns the message with the panic value.
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrPanicked, err.Value)
+}
+
+// Unwrap returns ErrPanicked, so the error could be checked using errors.Is.
+func (err *PanicError) Unwrap() error {
+	return ErrPanicked
+}
+
+// Job executes the function of the task within the context, it returns the
+// result and the error of the function.
+type Job func(ctx context.Context, task *Task) (interface{}, error)
+
+// Middleware wraps the Job to add behaviour around the execution, e.g.
+// logging or recovery. It shall call next to execute the function.
+type Middleware func(next Job) Job
+
+// WithMiddleware adds middlewares applied around executions of all tasks of
+// the Scheduler, they wrap the middlewares of the task. The first middleware
+// is the outermost one.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.middlewares = append(scheduler.middlewares, middlewares...)
+	}
+}
+
+// Use adds middlewares applied around executions of the task, the first
+// middleware is the outermost one. It shall be called before the task is
+// scheduled.
+func (task *Task) Use(middlewares ...Middleware) *Task {
+	task.Middlewares = append(task.Middlewares, middlewares...)
+	return task
+}
+
+// chainJob wraps the job with the middlewares, the first middleware is the
+// outermost one.
+func chainJob(job Job, middlewares ...[]Middleware) Job {
+	for index := len(middlewares) - 1; index >= 0; index-- {
+		for position := len(middlewares[index]) - 1; position >= 0; position-- {
+			job = middlewares[index][position](job)
+		}
+	}
+	return job
+}
+
+// functionJob returns the Job that calls the function with the task and
+// provided parameters.
+func functionJob(function interface{}, parameters ...interface{}) Job {
+	return func(ctx context.Context, task *Task) (interface{}, error) {
+		// Add scheduled task to the arguments of the executed function.
+		taskParameters := append([]interface{}{task}, parameters...)
+		return callFunction(ctx, function, taskParameters...)
+	}
+}
+
+// RecoveryMiddleware recovers the panic of the function and returns it as
+// PanicError, so the run is recorded as failed.
+func RecoveryMiddleware() Middleware {
+	return func(next Job) Job {
+		return func(ctx context.Context, task *Task) (result interface{}, err error) {
+			defer func() {
+				if value := recover(); value != nil {
+					result, err = nil, &PanicError{Value: value, Stack: debug.Stack()}
+				}
+			}()
+			return next(ctx, task)
+		}
+	}
+}
+
+// TimingMiddleware measures executions and passes their duration and error to
+// the observe function.
+func TimingMiddleware(observe func(task *Task, duration time.Duration, err error)) Middleware {
+	return func(next Job) Job {
+		return func(ctx context.Context, task *Task) (interface{}, error) {
+			started := time.Now()
+			result, err := next(ctx, task)
+			observe(task, time.Since(started), err)
+			return result, err
+		}
+	}
+}
+
+// LoggingMiddleware writes the start and the end of executions to the logger,
+// the standard logger is used if it is nil.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next Job) Job {
+		return func(ctx context.Context, task *Task) (interface{}, error) {
+			number := 0
+			if run := RunFromContext(ctx); run != nil {
+				number = run.Number
+			}
+			logger.Printf("task %s (%s) run %d started", task.Name, task.ID, number)
+			started := time.Now()
+			result, err := next(ctx, task)
+			if err != nil {
+				logger.Printf("task %s (%s) run %d failed after %s: %v", task.Name, task.ID, number, time.Since(started), err)
+			} else {
+				logger.Printf("task %s (%s) run %d succeeded after %s", task.Name, task.ID, number, time.Since(started))
+			}
+			return result, err
+		}
+	}
+}
+
+// SkipIfStillRunning skips the execution of the task while its previous
+// execution wrapped by the same middleware is still in progress. The run is
+// recorded as skipped with ErrTaskRunning. Unlike OverlapSkip, it applies to
+// executions started by chains and workflows as well.
+func SkipIfStillRunning() Middleware {
+	running := make(map[*Task]bool)
+	mutex := sync.Mutex{}
+	return func(next Job) Job {
+		return func(ctx context.Context, task *Task) (interface{}, error) {
+			mutex.Lock()
+			if running[task] {
+				mutex.Unlock()
+				return nil, fmt.Errorf("%w: %w", ErrSkipped, ErrTaskRunning)
+			}
+			running[task] = true
+			mutex.Unlock()
+
+			defer func() {
+				mutex.Lock()
+				delete(running, task)
+				mutex.Unlock()
+			}()
+			return next(ctx, task)
+		}
+	}
+}
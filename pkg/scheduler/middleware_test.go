@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestChainJob tests that middlewares of the scheduler wrap middlewares of the
+// task and the first middleware is the outermost one.
+func TestChainJob(t *testing.T) {
+	calls := make([]string, 0)
+	record := func(name string) Middleware {
+		return func(next Job) Job {
+			return func(ctx context.Context, task *Task) (interface{}, error) {
+				calls = append(calls, name+" before")
+				result, err := next(ctx, task)
+				calls = append(calls, name+" after")
+				return result, err
+			}
+		}
+	}
+
+	job := chainJob(func(ctx context.Context, task *Task) (interface{}, error) {
+		calls = append(calls, "job")
+		return "result", nil
+	}, []Middleware{record("global 1"), record("global 2")}, []Middleware{record("task")})
+
+	result, err := job(context.Background(), &Task{})
+	if result != "result" || err != nil {
+		t.Fatalf("Incorrect result of the job: %v, %v.", result, err)
+	}
+	expected := "global 1 before,global 2 before,task before,job,task after,global 2 after,global 1 after"
+	if strings.Join(calls, ",") != expected {
+		t.Fatalf("Incorrect order of the middlewares: %v.", calls)
+	}
+}
+
+// TestScheduler_Middleware tests that middlewares of the scheduler and the task
+// are applied around the scheduled function and the recovery middleware
+// records the panic as the failed run.
+func TestScheduler_Middleware(t *testing.T) {
+	observed := make(chan error, 1)
+	newScheduler := New(WithMiddleware(TimingMiddleware(func(task *Task, duration time.Duration, err error) {
+		observed <- err
+	}), RecoveryMiddleware()))
+	defer newScheduler.Shutdown(context.Background())
+
+	task := NewTask("", "Task", nil, nil, 0, nil, nil).Use(func(next Job) Job {
+		return func(ctx context.Context, task *Task) (interface{}, error) {
+			task.SetToContext("wrapped", true)
+			return next(ctx, task)
+		}
+	})
+	_ = newScheduler.Schedule(task, func(task *Task) {
+		panic("failure")
+	})
+	task.Wait()
+
+	run, _ := task.LastRun()
+	var panicError *PanicError
+	if run.Status != RunFailed || !errors.As(run.Err, &panicError) || panicError.Value != "failure" || !errors.Is(run.Err, ErrPanicked) {
+		t.Fatalf("Panic has not been recovered: %+v.", run)
+	}
+	if task.GetFromContext("wrapped") != true {
+		t.Fatalf("Middleware of the task has not been applied.")
+	}
+	select {
+	case err := <-observed:
+		if !errors.Is(err, ErrPanicked) {
+			t.Fatalf("Incorrect observed error: %v.", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Duration has not been observed.")
+	}
+}
+
+// TestLoggingMiddleware tests that the logging middleware writes the start and
+// the result of the execution.
+func TestLoggingMiddleware(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	job := LoggingMiddleware(log.New(buffer, "", 0))(func(ctx context.Context, task *Task) (interface{}, error) {
+		return nil, errors.New("failure")
+	})
+
+	run := &Run{Number: 3}
+	_, _ = job(contextWithRun(context.Background(), run), &Task{ID: "id", Name: "Task"})
+
+	output := buffer.String()
+	if !strings.Contains(output, "task Task (id) run 3 started") || !strings.Contains(output, "task Task (id) run 3 failed after") || !strings.Contains(output, "failure") {
+		t.Fatalf("Incorrect log output: %q.", output)
+	}
+}
+
+// TestSkipIfStillRunning tests that the execution is skipped and recorded as
+// skipped while the previous execution of the same task is in progress.
+func TestSkipIfStillRunning(t *testing.T) {
+	task := NewTask("", "Task", nil, nil, 0, nil, nil).Use(SkipIfStillRunning())
+
+	started, release := make(chan struct{}), make(chan struct{})
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+		_ = task.execute(context.Background(), func(task *Task) {
+			close(started)
+			<-release
+		})
+	}()
+	<-started
+
+	err := task.execute(context.Background(), func(task *Task) {})
+	if !errors.Is(err, ErrSkipped) || !errors.Is(err, ErrTaskRunning) {
+		t.Fatalf("Overlapping execution has not been skipped: %v.", err)
+	}
+	if run, _ := task.LastRun(); run.Status != RunSkipped {
+		t.Fatalf("Overlapping execution has not been recorded as skipped: %+v.", run)
+	}
+
+	close(release)
+	waitGroup.Wait()
+	if err = task.execute(context.Background(), func(task *Task) {}); err != nil {
+		t.Fatalf("Execution has been skipped after the previous one finished: %v.", err)
+	}
+}
+
+// TestScheduler_Middleware_Chain tests that chained runs go through the
+// middlewares and events of the scheduler, so the panic of the chained task is
+// recovered.
+func TestScheduler_Middleware_Chain(t *testing.T) {
+	failed := make(chan Event, 1)
+	newScheduler := New(WithMiddleware(RecoveryMiddleware()), WithListener(ListenerFunc(func(event Event) {
+		if event.Type == EventRunFailed {
+			failed <- event
+		}
+	})))
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	source := NewTask("", "Source", &start, nil, time.Hour, nil, nil)
+	chained := NewSimpleTask("Chained", 0)
+	_ = source.Chain(OnSuccess, chained, func(task *Task) {
+		panic("chained failure")
+	})
+	_ = newScheduler.Schedule(source, func(task *Task) {})
+	_, _ = newScheduler.TriggerTask(source)
+
+	select {
+	case event := <-failed:
+		if event.Task != chained || !errors.Is(event.Run.Err, ErrPanicked) {
+			t.Fatalf("Incorrect event of the chained run: %+v.", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Panic of the chained task has not been reported.")
+	}
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import "time"
+
+// DefaultWorkers is the number of workers that execute tasks, if other number
+// was not set using WithWorkers.
+const DefaultWorkers = 64
+
+// DefaultQueueSize is the number of runs that could wait for a free worker, if
+// other size was not set using WithQueueSize.
+const DefaultQueueSize = 1024
+
+// DefaultPriorityAging is how long run waits in the queue to gain one priority
+// level, if other value was not set using WithPriorityAging.
+const DefaultPriorityAging = time.Second
+
+// SaturationPolicy defines what happens with the run when all workers are busy
+// and the queue is full.
+type SaturationPolicy int
+
+const (
+	// SaturationBlock makes the timing loop wait until the run could be queued,
+	// so all other due tasks are delayed as well. It is the default policy.
+	SaturationBlock SaturationPolicy = iota
+	// SaturationDrop skips the run and records it as skipped in the task
+	// history with ErrSchedulerSaturated.
+	SaturationDrop
+	// SaturationDelay postpones the run by the delay provided to
+	// WithSaturationPolicy and tries to queue it again.
+	SaturationDelay
+)
+
+// Option configures the Scheduler created by New.
+type Option func(scheduler *Scheduler)
+
+// WithWorkers sets how many runs could be executed at the same time, values
+// lower than 1 are replaced by 1.
+func WithWorkers(workers int) Option {
+	return func(scheduler *Scheduler) {
+		if workers < 1 {
+			workers = 1
+		}
+		scheduler.workers = workers
+	}
+}
+
+// WithQueueSize sets how many runs could wait for a free worker, negative value
+// is replaced by 0, which means that run is queued only if some worker is idle.
+func WithQueueSize(size int) Option {
+	return func(scheduler *Scheduler) {
+		if size < 0 {
+			size = 0
+		}
+		scheduler.queueSize = size
+	}
+}
+
+// WithSaturationPolicy sets what happens with the run when all workers are busy
+// and the queue is full. The delay is used only by SaturationDelay, if it is
+// not positive, then one second is used.
+func WithSaturationPolicy(policy SaturationPolicy, delay time.Duration) Option {
+	return func(scheduler *Scheduler) {
+		if delay <= 0 {
+			delay = time.Second
+		}
+		scheduler.saturation = policy
+		scheduler.saturationDelay = delay
+	}
+}
+
+// WithPriorityAging sets how long run waits in the queue to gain one priority
+// level, so low-priority runs are eventually executed even if high-priority
+// runs are queued all the time. Aging is disabled if it is not positive, in this
+// case queued runs are executed strictly by priority.
+func WithPriorityAging(aging time.Duration) Option {
+	return func(scheduler *Scheduler) {
+		if aging < 0 {
+			aging = 0
+		}
+		scheduler.aging = aging
+	}
+}
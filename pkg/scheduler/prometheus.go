@@ -0,0 +1,259 @@
+package scheduler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are upper bounds in seconds of the histogram buckets used by
+// PrometheusMetrics, if other buckets were not provided.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// PrometheusMetrics implements Metrics and exposes them in the Prometheus text
+// exposition format, it could be used as http.Handler for the metrics
+// endpoint. The following metrics are exposed:
+//
+//	scheduler_tasks_registered                     gauge
+//	scheduler_runs_total{task,outcome}             counter, outcome is the RunStatus
+//	scheduler_run_duration_seconds{task}           histogram of finished runs
+//	scheduler_scheduling_lag_seconds{task}         histogram of start minus planned fire time
+//	scheduler_misfires_total{task}                 counter
+//	scheduler_workers / scheduler_workers_busy     gauges
+//	scheduler_queue_size / scheduler_queue_length  gauges
+//	scheduler_worker_saturation                    gauge, busy workers divided by workers
+type PrometheusMetrics struct {
+	buckets   []float64
+	tasks     int
+	runs      map[runKey]uint64
+	durations map[string]*histogram
+	lags      map[string]*histogram
+	misfires  map[string]uint64
+	busy      int
+	workers   int
+	queued    int
+	queueSize int
+	mutex     sync.Mutex
+}
+
+// runKey identifies the runs counter.
+type runKey struct {
+	task    string
+	outcome RunStatus
+}
+
+// histogram stores cumulative bucket counts.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics creates PrometheusMetrics with histograms using provided
+// bucket upper bounds in seconds, DefaultBuckets are used if none provided.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &PrometheusMetrics{
+		buckets:   sorted,
+		runs:      make(map[runKey]uint64),
+		durations: make(map[string]*histogram),
+		lags:      make(map[string]*histogram),
+		misfires:  make(map[string]uint64),
+	}
+}
+
+// SetTasks sets the number of registered tasks.
+func (metrics *PrometheusMetrics) SetTasks(count int) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.tasks = count
+}
+
+// ObserveRun counts the run by its outcome and records the duration of the
+// finished run.
+func (metrics *PrometheusMetrics) ObserveRun(name string, status RunStatus, duration time.Duration) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.runs[runKey{task: name, outcome: status}]++
+	if status != RunSkipped {
+		metrics.observe(metrics.durations, name, duration)
+	}
+}
+
+// ObserveLag records the scheduling lag of the run.
+func (metrics *PrometheusMetrics) ObserveLag(name string, lag time.Duration) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.observe(metrics.lags, name, lag)
+}
+
+// AddMisfires counts missed fire times.
+func (metrics *PrometheusMetrics) AddMisfires(name string, count int) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.misfires[name] += uint64(count)
+}
+
+// SetWorkers records the worker pool state.
+func (metrics *PrometheusMetrics) SetWorkers(busy int, workers int, queued int, queueSize int) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.busy, metrics.workers, metrics.queued, metrics.queueSize = busy, workers, queued, queueSize
+}
+
+// observe adds the value to the histogram of the task. It shall be called with
+// the metrics mutex locked.
+func (metrics *PrometheusMetrics) observe(histograms map[string]*histogram, name string, value time.Duration) {
+	item, ok := histograms[name]
+	if !ok {
+		item = &histogram{counts: make([]uint64, len(metrics.buckets))}
+		histograms[name] = item
+	}
+	seconds := value.Seconds()
+	for index, bound := range metrics.buckets {
+		if seconds <= bound {
+			item.counts[index]++
+		}
+	}
+	item.count++
+	item.sum += seconds
+}
+
+// ServeHTTP writes the metrics in the text exposition format.
+func (metrics *PrometheusMetrics) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = metrics.WriteTo(writer)
+}
+
+// WriteTo writes the metrics in the text exposition format to the writer.
+func (metrics *PrometheusMetrics) WriteTo(writer io.Writer) (int64, error) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	output := &countingWriter{writer: bufio.NewWriter(writer)}
+	output.metric("scheduler_tasks_registered", "gauge", "Number of tasks registered in the scheduler.")
+	output.sample("scheduler_tasks_registered", "", float64(metrics.tasks))
+
+	output.metric("scheduler_runs_total", "counter", "Number of runs by outcome.")
+	keys := make([]runKey, 0, len(metrics.runs))
+	for key := range metrics.runs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].task != keys[j].task {
+			return keys[i].task < keys[j].task
+		}
+		return keys[i].outcome < keys[j].outcome
+	})
+	for _, key := range keys {
+		output.sample("scheduler_runs_total", labels("task", key.task, "outcome", string(key.outcome)), float64(metrics.runs[key]))
+	}
+
+	output.metric("scheduler_run_duration_seconds", "histogram", "Duration of finished runs.")
+	metrics.writeHistograms(output, "scheduler_run_duration_seconds", metrics.durations)
+	output.metric("scheduler_scheduling_lag_seconds", "histogram", "Delay between the planned fire time and the start of runs.")
+	metrics.writeHistograms(output, "scheduler_scheduling_lag_seconds", metrics.lags)
+
+	output.metric("scheduler_misfires_total", "counter", "Number of missed fire times.")
+	for _, name := range sortedKeys(metrics.misfires) {
+		output.sample("scheduler_misfires_total", labels("task", name), float64(metrics.misfires[name]))
+	}
+
+	saturation := 0.0
+	if metrics.workers > 0 {
+		saturation = float64(metrics.busy) / float64(metrics.workers)
+	}
+	output.metric("scheduler_workers", "gauge", "Number of workers.")
+	output.sample("scheduler_workers", "", float64(metrics.workers))
+	output.metric("scheduler_workers_busy", "gauge", "Number of workers executing runs.")
+	output.sample("scheduler_workers_busy", "", float64(metrics.busy))
+	output.metric("scheduler_worker_saturation", "gauge", "Ratio of busy workers.")
+	output.sample("scheduler_worker_saturation", "", saturation)
+	output.metric("scheduler_queue_length", "gauge", "Number of runs waiting for a worker.")
+	output.sample("scheduler_queue_length", "", float64(metrics.queued))
+	output.metric("scheduler_queue_size", "gauge", "Number of runs that could wait for a worker.")
+	output.sample("scheduler_queue_size", "", float64(metrics.queueSize))
+
+	if err := output.writer.Flush(); err != nil && output.err == nil {
+		output.err = err
+	}
+	return output.count, output.err
+}
+
+// writeHistograms writes histograms of all tasks. It shall be called with the
+// metrics mutex locked.
+func (metrics *PrometheusMetrics) writeHistograms(output *countingWriter, name string, histograms map[string]*histogram) {
+	for _, task := range sortedKeys(histograms) {
+		item := histograms[task]
+		for index, bound := range metrics.buckets {
+			output.sample(name+"_bucket", labels("task", task, "le", formatFloat(bound)), float64(item.counts[index]))
+		}
+		output.sample(name+"_bucket", labels("task", task, "le", "+Inf"), float64(item.count))
+		output.sample(name+"_sum", labels("task", task), item.sum)
+		output.sample(name+"_count", labels("task", task), float64(item.count))
+	}
+}
+
+// countingWriter writes the exposition format and remembers the first error.
+type countingWriter struct {
+	writer *bufio.Writer
+	count  int64
+	err    error
+}
+
+// metric writes HELP and TYPE lines of the metric.
+func (output *countingWriter) metric(name string, kind string, help string) {
+	output.write(fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind))
+}
+
+// sample writes the sample of the metric with provided labels.
+func (output *countingWriter) sample(name string, labels string, value float64) {
+	output.write(name + labels + " " + formatFloat(value) + "\n")
+}
+
+// write writes the text unless the previous write failed.
+func (output *countingWriter) write(text string) {
+	if output.err != nil {
+		return
+	}
+	written, err := output.writer.WriteString(text)
+	output.count += int64(written)
+	output.err = err
+}
+
+// labels formats label pairs, values are escaped.
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for index := 0; index+1 < len(pairs); index += 2 {
+		parts = append(parts, pairs[index]+`="`+labelEscaper.Replace(pairs[index+1])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// labelEscaper escapes label values according to the exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatFloat formats the value in the shortest representation.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sortedKeys returns keys of the map in ascending order.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
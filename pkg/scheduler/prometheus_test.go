@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPrometheusMetrics tests the exposition format of the metrics, including
+// histogram buckets, skipped runs, misfires and escaping of label values.
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := NewPrometheusMetrics(1, 0.1)
+	metrics.SetTasks(3)
+	metrics.ObserveRun(`Report "daily"`, RunSucceeded, 50*time.Millisecond)
+	metrics.ObserveRun(`Report "daily"`, RunSucceeded, 2*time.Second)
+	metrics.ObserveRun(`Report "daily"`, RunSkipped, 0)
+	metrics.AddMisfires("Backup", 4)
+	metrics.SetWorkers(3, 4, 5, 10)
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("Incorrect content type: %s.", recorder.Header().Get("Content-Type"))
+	}
+
+	output := recorder.Body.String()
+	for _, line := range []string{
+		"# TYPE scheduler_run_duration_seconds histogram\n",
+		"scheduler_tasks_registered 3\n",
+		`scheduler_runs_total{task="Report \"daily\"",outcome="skipped"} 1` + "\n",
+		`scheduler_runs_total{task="Report \"daily\"",outcome="succeeded"} 2` + "\n",
+		`scheduler_run_duration_seconds_bucket{task="Report \"daily\"",le="0.1"} 1` + "\n",
+		`scheduler_run_duration_seconds_bucket{task="Report \"daily\"",le="1"} 1` + "\n",
+		`scheduler_run_duration_seconds_bucket{task="Report \"daily\"",le="+Inf"} 2` + "\n",
+		`scheduler_run_duration_seconds_sum{task="Report \"daily\""} 2.05` + "\n",
+		`scheduler_misfires_total{task="Backup"} 4` + "\n",
+		"scheduler_worker_saturation 0.75\n",
+		"scheduler_queue_length 5\n",
+		"scheduler_queue_size 10\n",
+	} {
+		if !strings.Contains(output, line) {
+			t.Fatalf("Metrics don't contain %q:\n%s", line, output)
+		}
+	}
+}
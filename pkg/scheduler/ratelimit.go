@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is recorded as the reason of the skipped run, when the rate
+// limit of the task is exceeded and ThrottleSkip policy is used.
+var ErrRateLimited = errors.New("rate limit of the task is exceeded")
+
+// ThrottlePolicy defines what happens with the run that would exceed the rate
+// limit.
+type ThrottlePolicy int
+
+const (
+	// ThrottleDelay postpones the run until the rate limit allows it, the delay
+	// is recorded in Run.Throttled. It is the default policy.
+	ThrottleDelay ThrottlePolicy = iota
+	// ThrottleSkip skips the run and records it as skipped in the task history
+	// with ErrRateLimited.
+	ThrottleSkip
+)
+
+// RateLimiter is a token bucket that limits how often runs of the tasks are
+// started. It could be attached to the single task using Task.RateLimiter, or
+// shared by several tasks using Scheduler.SetRateLimiter and Task.RateLimit.
+type RateLimiter struct {
+	// Limit is the number of runs allowed per Period.
+	Limit int
+	// Period is the time window of the Limit.
+	Period time.Duration
+	// Burst is the maximum number of runs that could be started at once, Limit
+	// is used if it is not positive.
+	Burst int
+	// Policy defines what happens with the run that would exceed the limit.
+	Policy ThrottlePolicy
+	// tokens stores the number of available runs.
+	tokens float64
+	// updated stores when tokens have been refilled last time.
+	updated time.Time
+	// mutex guards tokens.
+	mutex sync.Mutex
+}
+
+// NewRateLimiter creates a new RateLimiter that allows limit runs per period and
+// delays the runs that exceed the limit.
+func NewRateLimiter(limit int, period time.Duration) *RateLimiter {
+	return &RateLimiter{Limit: limit, Period: period}
+}
+
+// burst returns the capacity of the bucket.
+func (limiter *RateLimiter) burst() float64 {
+	if limiter.Burst > 0 {
+		return float64(limiter.Burst)
+	}
+	return float64(limiter.Limit)
+}
+
+// refill adds tokens accumulated since the last refill, the bucket is full on
+// the first use. It shall be called with the limiter mutex locked.
+func (limiter *RateLimiter) refill(now time.Time) {
+	if limiter.updated.IsZero() {
+		limiter.tokens = limiter.burst()
+	} else if now.After(limiter.updated) && limiter.Period > 0 {
+		elapsed := float64(now.Sub(limiter.updated))
+		limiter.tokens += elapsed * float64(limiter.Limit) / float64(limiter.Period)
+		if limiter.tokens > limiter.burst() {
+			limiter.tokens = limiter.burst()
+		}
+	}
+	limiter.updated = now
+}
+
+// delay returns how long the run shall wait until a token is available, zero
+// means that the run is allowed now. Limiter without positive Limit or Period
+// allows all runs.
+func (limiter *RateLimiter) delay(now time.Time) time.Duration {
+	if limiter.Limit <= 0 || limiter.Period <= 0 {
+		return 0
+	}
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.refill(now)
+	if limiter.tokens >= 1 {
+		return 0
+	}
+	missing := 1 - limiter.tokens
+	wait := time.Duration(missing * float64(limiter.Period) / float64(limiter.Limit))
+	if wait <= 0 {
+		wait = time.Nanosecond
+	}
+	return wait
+}
+
+// take consumes a token for the run.
+func (limiter *RateLimiter) take(now time.Time) {
+	if limiter.Limit <= 0 || limiter.Period <= 0 {
+		return
+	}
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.refill(now)
+	limiter.tokens--
+}
+
+// WithRateLimiter registers the shared rate limiter, see
+// Scheduler.SetRateLimiter.
+func WithRateLimiter(name string, limiter *RateLimiter) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.setRateLimiter(name, limiter)
+	}
+}
+
+// SetRateLimiter registers the rate limiter with provided name, it is shared by
+// all tasks with the same Task.RateLimit. Nil limiter removes the registered
+// one.
+func (scheduler *Scheduler) SetRateLimiter(name string, limiter *RateLimiter) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	scheduler.setRateLimiter(name, limiter)
+}
+
+// setRateLimiter registers the rate limiter, it shall be called with the
+// scheduler mutex locked.
+func (scheduler *Scheduler) setRateLimiter(name string, limiter *RateLimiter) {
+	if limiter == nil {
+		delete(scheduler.limiters, name)
+		return
+	}
+	if scheduler.limiters == nil {
+		scheduler.limiters = make(map[string]*RateLimiter)
+	}
+	scheduler.limiters[name] = limiter
+}
+
+// rateLimiters returns limiters that apply to the task.
+func (scheduler *Scheduler) rateLimiters(task *Task) []*RateLimiter {
+	limiters := make([]*RateLimiter, 0, 2)
+	if task.RateLimiter != nil {
+		limiters = append(limiters, task.RateLimiter)
+	}
+	if limiter, ok := scheduler.limiters[task.RateLimit]; ok && task.RateLimit != "" && limiter != task.RateLimiter {
+		limiters = append(limiters, limiter)
+	}
+	return limiters
+}
+
+// throttle checks the rate limiters of the task. If all of them allow the run,
+// their tokens are consumed and it returns zero. Otherwise it returns how long
+// the run shall be delayed and whether it shall be skipped instead. It shall be
+// called with the scheduler mutex locked.
+func (scheduler *Scheduler) throttle(task *Task, now time.Time) (time.Duration, bool) {
+	limiters := scheduler.rateLimiters(task)
+
+	var wait time.Duration
+	skip := false
+	for _, limiter := range limiters {
+		if delay := limiter.delay(now); delay > 0 {
+			skip = skip || limiter.Policy == ThrottleSkip
+			if delay > wait {
+				wait = delay
+			}
+		}
+	}
+	if wait > 0 {
+		return wait, skip
+	}
+
+	for _, limiter := range limiters {
+		limiter.take(now)
+	}
+	return 0, false
+}
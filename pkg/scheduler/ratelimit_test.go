@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRateLimiter tests that RateLimiter allows burst of runs and then refills
+// tokens according to the limit.
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(2, 100*time.Millisecond)
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for index := 0; index < 2; index++ {
+		if delay := limiter.delay(now); delay != 0 {
+			t.Fatalf("Run %d has been delayed by %s.", index+1, delay)
+		}
+		limiter.take(now)
+	}
+
+	if delay := limiter.delay(now); delay != 50*time.Millisecond {
+		t.Fatalf("Incorrect delay. Expected: %s. Actual: %s.", 50*time.Millisecond, delay)
+	}
+
+	if delay := limiter.delay(now.Add(50 * time.Millisecond)); delay != 0 {
+		t.Fatalf("Token has not been refilled, delay: %s.", delay)
+	}
+
+	if delay := (&RateLimiter{}).delay(now); delay != 0 {
+		t.Fatalf("Limiter without limit has delayed the run by %s.", delay)
+	}
+}
+
+// TestScheduler_RateLimiter_Delay tests that runs of the tasks sharing the rate
+// limiter are delayed and the delay is recorded in the history.
+func TestScheduler_RateLimiter_Delay(t *testing.T) {
+	newScheduler := New(WithRateLimiter("api", NewRateLimiter(2, 200*time.Millisecond)))
+	defer newScheduler.Shutdown(context.Background())
+
+	started := time.Now()
+	tasks := make([]*Task, 0)
+	for index := 0; index < 4; index++ {
+		task := NewTask("", "API", nil, nil, 0, nil, nil)
+		task.RateLimit = "api"
+		_ = newScheduler.Schedule(task, func(task *Task) {})
+		tasks = append(tasks, task)
+	}
+
+	throttled := 0
+	for _, task := range tasks {
+		task.Wait()
+		run, _ := task.LastRun()
+		if run.Status != RunSucceeded {
+			t.Fatalf("Throttled run has not been executed: %+v.", run)
+		}
+		if run.Throttled > 0 {
+			throttled++
+		}
+	}
+
+	if throttled != 2 {
+		t.Fatalf("Incorrect number of throttled runs. Expected: 2. Actual: %d.", throttled)
+	}
+	if elapsed := time.Since(started); elapsed < 180*time.Millisecond {
+		t.Fatalf("Runs have not been delayed, elapsed: %s.", elapsed)
+	}
+}
+
+// TestScheduler_RateLimiter_Skip tests that runs exceeding the rate limit are
+// skipped with ThrottleSkip policy.
+func TestScheduler_RateLimiter_Skip(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	limiter := NewRateLimiter(1, time.Hour)
+	limiter.Policy = ThrottleSkip
+
+	succeeded, skipped := 0, 0
+	for index := 0; index < 3; index++ {
+		task := NewTask("", "API", nil, nil, 0, nil, nil)
+		task.RateLimiter = limiter
+		_ = newScheduler.Schedule(task, func(task *Task) {})
+		task.Wait()
+
+		run, _ := task.LastRun()
+		switch {
+		case run.Status == RunSucceeded:
+			succeeded++
+		case run.Status == RunSkipped && errors.Is(run.Err, ErrRateLimited):
+			skipped++
+		}
+	}
+
+	if succeeded != 1 || skipped != 2 {
+		t.Fatalf("Incorrect outcome of the runs. Succeeded: %d. Skipped: %d.", succeeded, skipped)
+	}
+}
@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultHistoryLimit is the number of the latest runs kept by a Task, if other
+// limit was not set using Task.SetHistoryLimit.
+const DefaultHistoryLimit = 10
+
+// RunStatus represents state of the single task execution.
+type RunStatus string
+
+const (
+	// RunRunning means that execution has started but not yet finished.
+	RunRunning RunStatus = "running"
+	// RunSucceeded means that function has returned without an error.
+	RunSucceeded RunStatus = "succeeded"
+	// RunFailed means that function has returned an error.
+	RunFailed RunStatus = "failed"
+	// RunSkipped means that function has not been executed at the fire time,
+	// Err stores the reason.
+	RunSkipped RunStatus = "skipped"
+)
+
+// Run is a record about the single execution of the Task.
+type Run struct {
+	// Number is a sequence number of the execution within the task, starting
+	// from 1.
+	Number int `json:"number"`
+	// Scheduled stores the planned fire time of the execution.
+	Scheduled time.Time `json:"scheduled"`
+	// Started stores time when the execution has started.
+	Started time.Time `json:"started"`
+	// Finished stores time when the execution has finished, it is zero while
+	// the execution is running.
+	Finished time.Time `json:"finished,omitempty"`
+	// Status stores state of the execution.
+	Status RunStatus `json:"status"`
+	// Err stores error returned by the function, or the reason why the
+	// execution has been skipped.
+	Err error `json:"-"`
+	// Throttled stores how long the execution has been delayed by rate limiters.
+	Throttled time.Duration `json:"throttled,omitempty"`
+	// Triggered is set if the execution has been triggered on demand using
+	// Scheduler.TriggerTask.
+	Triggered bool `json:"triggered,omitempty"`
+	// Result stores the first non-error value returned by the function, for
+	// example CommandResult for the Command job.
+	Result interface{} `json:"result,omitempty"`
+}
+
+// Duration returns how long the execution took, for the running execution it
+// returns time elapsed since the start.
+func (run *Run) Duration() time.Duration {
+	if run.Finished.IsZero() {
+		return time.Since(run.Started)
+	}
+	return run.Finished.Sub(run.Started)
+}
+
+// newTaskContext creates a context for the task executions, it is cancelled
+// when the task is stopped.
+func newTaskContext() (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.Background())
+}
+
+// runContextKey is the context key under which the current Run is stored.
+type runContextKey struct{}
+
+// contextWithRun returns a copy of the context that carries provided Run.
+func contextWithRun(ctx context.Context, run *Run) context.Context {
+	return context.WithValue(ctx, runContextKey{}, run)
+}
+
+// RunFromContext returns Run of the current execution from the context passed to
+// the scheduled function, or nil if context doesn't belong to any execution.
+// The returned record is owned by the Task and shall not be modified, the
+// function shall return its output instead.
+func RunFromContext(ctx context.Context) *Run {
+	run, _ := ctx.Value(runContextKey{}).(*Run)
+	return run
+}
+
+// schedulerContextKey is the context key under which the Scheduler executing
+// the current Run is stored.
+type schedulerContextKey struct{}
+
+// contextWithScheduler returns a copy of the context that carries the Scheduler
+// executing the run, if any.
+func contextWithScheduler(ctx context.Context, scheduler *Scheduler) context.Context {
+	if scheduler == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, schedulerContextKey{}, scheduler)
+}
+
+// schedulerFromContext returns the Scheduler executing the current run or nil.
+func schedulerFromContext(ctx context.Context) *Scheduler {
+	scheduler, _ := ctx.Value(schedulerContextKey{}).(*Scheduler)
+	return scheduler
+}
+
+// runPlan describes how the execution has been planned.
+type runPlan struct {
+	// scheduled stores the planned fire time, the start time is used if it is
+	// zero.
+	scheduled time.Time
+	// throttled stores how long the execution has been delayed by rate limiters.
+	throttled time.Duration
+	// triggered is set for the execution triggered by Scheduler.TriggerTask.
+	triggered bool
+	// handle is finished with the record of the execution, it could be nil.
+	handle *RunHandle
+	// scheduler receives events about the execution, it could be nil.
+	scheduler *Scheduler
+}
+
+// startRun creates a new running record for the execution according to the
+// plan and adds it to the task history.
+func (task *Task) startRun(plan runPlan) *Run {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	now := time.Now()
+	scheduled := plan.scheduled
+	if scheduled.IsZero() {
+		scheduled = now
+	}
+
+	task.runs++
+	run := &Run{Number: task.runs, Scheduled: scheduled, Started: now, Status: RunRunning, Throttled: plan.throttled, Triggered: plan.triggered}
+	task.addRun(run)
+
+	return run
+}
+
+// skipRun records that execution planned according to the plan has not been
+// executed because of the provided reason.
+func (task *Task) skipRun(plan runPlan, reason error) Run {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	now := time.Now()
+	task.runs++
+	run := &Run{Number: task.runs, Scheduled: plan.scheduled, Started: now, Finished: now, Status: RunSkipped, Err: reason, Triggered: plan.triggered}
+	task.addRun(run)
+
+	return *run
+}
+
+// addRun adds the record to the history and removes the oldest records above
+// the history limit. It shall be called with the task mutex locked.
+func (task *Task) addRun(run *Run) {
+	limit := task.historyLimit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	task.history = append(task.history, run)
+	if len(task.history) > limit {
+		task.history = append([]*Run(nil), task.history[len(task.history)-limit:]...)
+	}
+}
+
+// finishRun marks the run as completed with the provided result and error, it
+// returns a copy of the completed record.
+func (task *Task) finishRun(run *Run, result interface{}, err error) Run {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	run.Finished = time.Now()
+	run.Result = result
+	run.Err = err
+	if errors.Is(err, ErrSkipped) {
+		run.Status = RunSkipped
+	} else if err != nil {
+		run.Status = RunFailed
+	} else {
+		run.Status = RunSucceeded
+	}
+	return *run
+}
+
+// History returns copies of the latest task runs, from the oldest to the newest.
+func (task *Task) History() []Run {
+	task.mutex.RLock()
+	defer task.mutex.RUnlock()
+
+	history := make([]Run, len(task.history))
+	for index, run := range task.history {
+		history[index] = *run
+	}
+	return history
+}
+
+// LastRun returns copy of the latest task run, the second value is false if the
+// task has not been executed yet.
+func (task *Task) LastRun() (Run, bool) {
+	task.mutex.RLock()
+	defer task.mutex.RUnlock()
+
+	if len(task.history) == 0 {
+		return Run{}, false
+	}
+	return *task.history[len(task.history)-1], true
+}
+
+// SetHistoryLimit sets how many latest runs are kept in the task history,
+// non-positive value restores DefaultHistoryLimit.
+func (task *Task) SetHistoryLimit(limit int) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	task.historyLimit = limit
+	if limit > 0 && len(task.history) > limit {
+		task.history = append([]*Run(nil), task.history[len(task.history)-limit:]...)
+	}
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTask_SetHistoryLimit tests that Task keeps only configured number of the
+// latest runs.
+func TestTask_SetHistoryLimit(t *testing.T) {
+	task := NewSimpleTask("", time.Second)
+	task.SetHistoryLimit(2)
+
+	for index := 0; index < 5; index++ {
+		task.finishRun(task.startRun(runPlan{}), nil, nil)
+	}
+
+	history := task.History()
+	if len(history) != 2 || history[0].Number != 4 || history[1].Number != 5 {
+		t.Fatalf("Incorrect history after limit has been applied: %+v.", history)
+	}
+}
+
+// TestTask_LastRun tests that Task.LastRun returns the latest run and reports
+// when task has not been executed.
+func TestTask_LastRun(t *testing.T) {
+	task := NewSimpleTask("", time.Second)
+
+	if _, ok := task.LastRun(); ok {
+		t.Fatalf("Task without executions has returned the last run.")
+	}
+
+	task.finishRun(task.startRun(runPlan{}), nil, errors.New("failure"))
+
+	run, ok := task.LastRun()
+	if !ok || run.Number != 1 || run.Status != RunFailed || run.Duration() < 0 {
+		t.Fatalf("Incorrect last run: %+v.", run)
+	}
+}
+
+// TestRunFromContext tests that RunFromContext returns run stored in the
+// context and nil for other contexts.
+func TestRunFromContext(t *testing.T) {
+	run := &Run{Number: 1}
+
+	if RunFromContext(contextWithRun(context.Background(), run)) != run {
+		t.Fatalf("Run has not been found in the context.")
+	}
+
+	if RunFromContext(context.Background()) != nil {
+		t.Fatalf("Run has been found in the empty context.")
+	}
+}
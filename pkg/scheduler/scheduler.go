@@ -3,24 +3,128 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"log/slog"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Scheduler struct that stores scheduled task.
+// ErrSchedulerShutdown is returned when task is scheduled after
+// Scheduler.Shutdown has been called.
+var ErrSchedulerShutdown = errors.New("scheduler has been shut down")
+
+// Scheduler struct that stores scheduled task. It fires tasks from the single
+// timing loop and executes them in the bounded pool of workers, both are
+// started with the first scheduled task.
 type Scheduler struct {
-	// Tasks stores list of scheduled Task.
+	// Tasks stores list of scheduled Task. It is indexed by the Scheduler, the
+	// order of tasks is unspecified, removal of the task moves the last task to
+	// its place. It shall not be modified directly, unless it is replaced with
+	// the list of a different length. ID and Name of the scheduled task shall
+	// not be changed.
 	Tasks []*Task
+	// index provides lookup of Tasks by pointer, ID and name.
+	index taskIndex
+	// workers stores the number of workers that execute tasks.
+	workers int
+	// queueSize stores how many runs could wait for a free worker.
+	queueSize int
+	// saturation defines what happens with the run when the queue is full.
+	saturation SaturationPolicy
+	// saturationDelay stores how long run is postponed by SaturationDelay.
+	saturationDelay time.Duration
+	// timers stores scheduled tasks ordered by their next fire time.
+	timers taskHeap
+	// queue stores runs waiting for a free worker ordered by priority.
+	queue dispatchQueue
+	// sequence numbers queued runs to keep FIFO order within the same priority.
+	sequence uint64
+	// duplicates defines what happens with the task whose name is already used.
+	duplicates DuplicatePolicy
+	// limiters stores shared rate limiters by name.
+	limiters map[string]*RateLimiter
+	// groups stores concurrency groups by name.
+	groups map[string]*taskGroup
+	// aging stores how long run waits in the queue to gain one priority level.
+	aging time.Duration
+	// busy counts workers executing a run.
+	busy int
+	// active counts queued and executing runs by task.
+	active map[*Task]int
+	// executing tracks runs executed by workers.
+	executing sync.WaitGroup
+	// started is set once the timing loop and workers are running.
+	started bool
+	// shutdown is set once Scheduler.Shutdown has been called.
+	shutdown bool
+	// wake notifies the timing loop that the next fire time has changed.
+	wake chan struct{}
+	// work notifies workers that a run has been queued.
+	work *sync.Cond
+	// space notifies the timing loop that the queue has space.
+	space *sync.Cond
+	// middlewares are applied around the executions of all tasks.
+	middlewares []Middleware
+	// logger receives events of the scheduler, they are not logged if it is nil.
+	logger *slog.Logger
+	// logLevels overrides levels used to log events.
+	logLevels map[EventType]slog.Level
+	// tracer opens the span around every execution.
+	tracer Tracer
+	// metrics receives measurements of the scheduler, it could be nil.
+	metrics Metrics
+	// events stores listeners and subscriptions.
+	events eventBus
+	// mutex guards the scheduler state.
+	mutex sync.Mutex
 }
 
-// New creates a new Scheduler object.
-func New() *Scheduler {
-	return &Scheduler{}
+// New creates a new Scheduler object configured with provided options.
+func New(options ...Option) *Scheduler {
+	scheduler := &Scheduler{
+		workers:    DefaultWorkers,
+		queueSize:  DefaultQueueSize,
+		saturation: SaturationBlock,
+		aging:      DefaultPriorityAging,
+		tracer:     NoopTracer{},
+		active:     make(map[*Task]int),
+		wake:       make(chan struct{}, 1),
+	}
+	scheduler.work = sync.NewCond(&scheduler.mutex)
+	scheduler.space = sync.NewCond(&scheduler.mutex)
+	for _, option := range options {
+		option(scheduler)
+	}
+	return scheduler
 }
 
+// OverlapPolicy defines what happens when the task is due while its previous
+// run has not finished yet.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the new run and records it as skipped in the task
+	// history. It is the default policy.
+	OverlapSkip OverlapPolicy = iota
+	// OverlapAllow executes the new run concurrently with the previous one.
+	OverlapAllow
+)
+
+const (
+	// PriorityLow is a priority for the tasks that could wait, e.g. clean-ups.
+	PriorityLow = -10
+	// PriorityNormal is the default priority of the task.
+	PriorityNormal = 0
+	// PriorityHigh is a priority for the critical tasks, e.g. health-checks.
+	PriorityHigh = 10
+)
+
 // Task represent a thing that could be scheduled using Scheduler.
 type Task struct {
 	// ID unique value to distinguish different tasks, could be custom, but it is
@@ -36,14 +140,106 @@ type Task struct {
 	// scheduler.
 	Duration *time.Duration `json:"duration,omitempty"`
 	// Interval stores information how often this task shall be triggered by
-	// scheduler.
+	// scheduler. If it is not positive, the task is triggered only once.
 	Interval time.Duration `json:"interval"`
+	// Cron stores the cron expression parsed by ParseCron, if it is set, then
+	// the task is triggered at the matching times at or after the start time
+	// instead of every Interval.
+	Cron string `json:"cron,omitempty"`
+	// Jitter delays every fire time by a pseudo-random duration between zero and
+	// Jitter, so tasks with the same schedule don't fire at once.
+	Jitter time.Duration `json:"jitter,omitempty"`
+	// JitterSeed is used to derive the jitter of every fire time, the task ID is
+	// used if it is zero. The same seed gives the same delays.
+	JitterSeed int64 `json:"jitter_seed,omitempty"`
+	// Overlap defines what happens when the task is due while its previous run
+	// has not finished yet.
+	Overlap OverlapPolicy `json:"overlap,omitempty"`
+	// Priority defines which runs are executed first, when several tasks are due
+	// at the same time or all workers are busy. Higher value means higher
+	// priority, PriorityNormal is used by default.
+	Priority int `json:"priority,omitempty"`
+	// Group is the name of the concurrency group joined by the task, runs of all
+	// tasks in the group are limited by Scheduler.SetGroupLimit.
+	Group string `json:"group,omitempty"`
+	// RateLimiter limits how often the task is started, it could be shared by
+	// several tasks.
+	RateLimiter *RateLimiter `json:"-"`
+	// RateLimit is the name of the shared rate limiter registered using
+	// Scheduler.SetRateLimiter.
+	RateLimit string `json:"rate_limit,omitempty"`
+	// Tags stores arbitrary tags of the task, they could be used in the
+	// Selector with TagKey.
+	Tags []string `json:"tags,omitempty"`
+	// Labels stores key/value labels of the task, they are used by the Selector.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Calendar defines days when the task could be fired, fire times on other
+	// days are handled according to the CalendarPolicy.
+	Calendar Calendar `json:"-"`
+	// CalendarPolicy defines what happens with fire times that are not
+	// contained in the Calendar, CalendarSkip is used by default.
+	CalendarPolicy CalendarPolicy `json:"calendar_policy,omitempty"`
+	// Location is the time zone where the Cron expression and the Calendar are
+	// evaluated, and the Interval of whole days is advanced in calendar days,
+	// so "every day at 09:00" keeps its local time across daylight saving time
+	// transitions. If it is nil, the location of the start time is used and the
+	// Interval is the elapsed time.
+	Location *time.Location `json:"-"`
+	// Middlewares are applied around the executions of the task, inside the
+	// middlewares of the Scheduler.
+	Middlewares []Middleware `json:"-"`
 	// stopSignal stores channel for task termination, it terminates the whole task,
 	// not only current execution.
 	stopSignal chan bool
 	// context stores additional key-value data that are shared between different
 	// task executions.
 	context map[string]interface{}
+	// ctx is passed to the executions, it is cancelled when the task is stopped.
+	ctx context.Context
+	// cancel cancels ctx of the task.
+	cancel context.CancelFunc
+	// runs counts task executions.
+	runs int
+	// history stores the latest task executions.
+	history []*Run
+	// historyLimit stores how many executions are kept in the history.
+	historyLimit int
+	// chains stores tasks executed after the task runs.
+	chains []chainLink
+	// mutex guards context and history that are accessed by the executions, and
+	// the schedule changed by Scheduler.UpdateTask.
+	mutex sync.RWMutex
+	// scheduler is the Scheduler that fires the task, it is changed with both
+	// the scheduler mutex and the task mutex locked.
+	scheduler *Scheduler
+	// function is called on every run with the task and parameters.
+	function interface{}
+	// parameters are passed to the function after the task.
+	parameters []interface{}
+	// planned stores the regular fire time that will be served next.
+	planned time.Time
+	// nextFire stores when the task shall be fired next, it differs from
+	// planned when the run has been postponed.
+	nextFire time.Time
+	// end stores time after which task is not fired anymore, zero if unlimited.
+	end time.Time
+	// cron stores the parsed Cron expression.
+	cron *CronSchedule
+	// last stores the planned time of the last fire, it is zero if the task has
+	// not been fired yet.
+	last time.Time
+	// fired is set once the task has been fired by the timing loop for the
+	// first time.
+	fired bool
+	// paused is set while firing of the task is suspended by
+	// Scheduler.PauseTask.
+	paused atomic.Bool
+	// throttled stores when the current run has been delayed by rate limiters
+	// for the first time, it is zero if the run is not delayed.
+	throttled time.Time
+	// heapIndex stores position of the task in the Scheduler timers, -1 if the
+	// task is not waiting for the fire time.
+	heapIndex int
 }
 
 // NewTask creates a new task struct, it handles default value initialization for
@@ -66,7 +262,7 @@ func NewTask(id string, name string, start *time.Time, duration *time.Duration,
 		context = make(map[string]interface{})
 	}
 
-	return &Task{
+	task := &Task{
 		ID:         id,
 		Name:       name,
 		Start:      start,
@@ -75,6 +271,9 @@ func NewTask(id string, name string, start *time.Time, duration *time.Duration,
 		stopSignal: stopSignal,
 		context:    context,
 	}
+	task.ctx, task.cancel = newTaskContext()
+
+	return task
 }
 
 // NewSimpleTask creates a new task struct where all parameters have default
@@ -88,146 +287,265 @@ func (task *Task) String() string {
 	stringBuilder := strings.Builder{}
 	stringBuilder.WriteString(fmt.Sprintf("ID: %s\n", task.ID))
 	stringBuilder.WriteString(fmt.Sprintf("Name: %s\n", task.Name))
-	stringBuilder.WriteString(fmt.Sprintf("Start: %s\n", task.Start.String()))
-	stringBuilder.WriteString(fmt.Sprintf("Duration: %s\n", task.Duration.String()))
-	stringBuilder.WriteString(fmt.Sprintf("Interval: %s\n", task.Interval.String()))
+	stringBuilder.WriteString(fmt.Sprintf("Schedule: %s\n", task.Describe()))
+	task.mutex.RLock()
 	stringBuilder.WriteString(fmt.Sprintf("Context: %v", task.context))
+	task.mutex.RUnlock()
 	return stringBuilder.String()
 }
 
-// FindTaskIndex searches through tasks array for the scheduled task and returns
-// its index.
+// FindTaskIndex returns index of the scheduled task in the Scheduler.Tasks or
+// -1 if the task is not scheduled.
 func (scheduler *Scheduler) FindTaskIndex(scheduledTask *Task) int {
-	for index, task := range scheduler.Tasks {
-		if task == scheduledTask {
-			return index
-		}
-	}
-	return -1
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	position, _ := scheduler.taskPosition(scheduledTask)
+	return position
 }
 
-// FindTaskByName searches through tasks array for the scheduled task with
-// provided name and returns it. If task with provided name was not found, then
-// returns nil.
+// FindTaskByName returns the scheduled task with provided name. If several tasks
+// have the same name, then the one scheduled first is returned. If task with
+// provided name was not found, then returns nil.
 func (scheduler *Scheduler) FindTaskByName(name string) *Task {
-	for _, task := range scheduler.Tasks {
-		if task.Name == name {
-			return task
-		}
-	}
-	return nil
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return firstIndexed(scheduler.tasksIndex().byName, name)
 }
 
-// FindTaskByID searches through tasks array for the scheduled task with provided
-// ID and returns it. If task with provided ID was not found, then returns nil.
+// FindTaskByID returns the scheduled task with provided ID. If task with
+// provided ID was not found, then returns nil.
 func (scheduler *Scheduler) FindTaskByID(id string) *Task {
-	for _, task := range scheduler.Tasks {
-		if task.ID == id {
-			return task
-		}
-	}
-	return nil
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return firstIndexed(scheduler.tasksIndex().byID, id)
 }
 
-// ScheduleTask starts a Go routine that runs a provided function with given
-// parameters. It stops either after a specified duration or when a stop signal
-// is received, whichever comes first. If duration is nil, it only stops when a
-// stop signal is received.
+// ScheduleTask creates a new Task and schedules it using Scheduler.Schedule. The
+// task runs the provided function with given parameters at the start time and
+// then every interval. It stops either after a specified duration or when a stop
+// signal is received, whichever comes first. If duration is nil, it only stops
+// when a stop signal is received. It returns nil if the task could not be
+// scheduled, use Scheduler.Schedule to get the reason. If the task with the
+// same name is kept because of DuplicateIgnore, then the existing task is
+// returned.
 func (scheduler *Scheduler) ScheduleTask(name string, startTime *time.Time, duration *time.Duration, interval time.Duration, function interface{}, parameters ...interface{}) *Task {
-	// Creates termination channel for the new task.
-	var terminationChannel = make(chan bool)
-
-	// Set default start time, if it was not provided.
-	if startTime == nil {
-		start := time.Now()
-		startTime = &start
+	scheduledTask := NewTask("", name, startTime, duration, interval, nil, nil)
+	existing, err := scheduler.schedule(scheduledTask, function, parameters...)
+	if err != nil {
+		return nil
 	}
+	if existing != nil {
+		return existing
+	}
+	return scheduledTask
+}
 
-	// Calculate the duration until the start time.
-	waitDuration := time.Until(*startTime)
+// Schedule adds prepared task to the scheduler, so all its attributes could be
+// set before the first run. The task is fired at its start time and then every
+// interval until the end of its duration or until it is stopped. On every fire
+// the run is queued for the pool of workers, which executes the function with
+// given parameters.
+//
+// The function receives the scheduled Task as the first parameter followed by
+// the provided parameters. If the function declares context.Context before the
+// Task, it receives the context of the current execution, which carries the
+// Run record and is cancelled when the task is stopped. If the last value
+// returned by the function is a non-nil error, the execution is recorded as
+// failed in the task history, the first value that is not an error is stored
+// as the result of the execution.
+//
+// It returns an error if function is not a function, the task is already
+// scheduled or the scheduler has been shut down. If the Scheduler is created
+// with WithUniqueNames, the task with the same name as already scheduled task
+// is handled according to the DuplicatePolicy.
+func (scheduler *Scheduler) Schedule(task *Task, function interface{}, parameters ...interface{}) error {
+	_, err := scheduler.schedule(task, function, parameters...)
+	return err
+}
 
-	// Create a new Task for Scheduler.
-	scheduledTask := &Task{
-		ID:         uuid.New().String(),
-		Name:       name,
-		Start:      startTime,
-		Duration:   duration,
-		Interval:   interval,
-		stopSignal: terminationChannel,
-		context:    make(map[string]interface{}),
+// schedule adds the task to the scheduler, it returns the existing task with
+// the same name if the task has not been scheduled because of the
+// DuplicatePolicy.
+func (scheduler *Scheduler) schedule(task *Task, function interface{}, parameters ...interface{}) (*Task, error) {
+	if reflect.ValueOf(function).Kind() != reflect.Func {
+		return nil, fmt.Errorf("task with id: %s cannot be scheduled, because provided argument is not a function", task.ID)
 	}
 
-	go func() {
-		// If the start time is in the future, wait until then to start the task.
-		if waitDuration > 0 {
-			<-time.After(waitDuration)
-		}
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
 
-		// Add scheduled task to the arguments of the executed function
-		parameters := append([]interface{}{scheduledTask}, parameters...)
+	if scheduler.shutdown {
+		return nil, ErrSchedulerShutdown
+	}
+	if task.scheduler != nil {
+		return nil, fmt.Errorf("task with id: %s is already scheduled", task.ID)
+	}
+	if err := task.parseCron(); err != nil {
+		return nil, err
+	}
+	if existing, err := scheduler.resolveDuplicate(task); existing != nil || err != nil {
+		return existing, err
+	}
 
-		// After waiting until the start time, execute the task once immediately.
-		callFunction(function, parameters...)
+	task.initialize()
+	task.setOwner(scheduler)
+	task.function = function
+	task.parameters = parameters
+	task.planned = task.firstFireTime(*task.Start)
+	task.end = time.Time{}
+	if task.Duration != nil {
+		task.end = task.Start.Add(*task.Duration)
+	}
 
-		// If a duration is specified, calculate the end time from the start time.
-		var endTime time.Time
-		if duration != nil {
-			endTime = startTime.Add(*duration)
-		}
+	scheduler.start()
+	scheduler.addTask(task)
+	scheduler.emit(EventTaskAdded, task, nil)
+	scheduler.reschedule(task)
 
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-		defer scheduler.StopTask(scheduledTask)
-
-		for {
-			select {
-			case <-terminationChannel: // If a terminationChannel signal is received, stop the task.
-				return
-			case tick := <-ticker.C: // On each tick, check if the end time has been reached.
-				if duration != nil && tick.After(endTime) {
-					return // If the current time is after the end time, stop the task.
-				}
-				callFunction(function, parameters...)
-			}
-		}
-	}()
+	return nil, nil
+}
 
-	// Add new Task to Scheduler tasks list.
-	scheduler.Tasks = append(scheduler.Tasks, scheduledTask)
+// initialize sets default values for the task that was not created by NewTask.
+func (task *Task) initialize() {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
 
-	return scheduledTask
+	if task.ID == "" {
+		task.ID = uuid.New().String()
+	}
+	if task.Start == nil {
+		start := time.Now()
+		task.Start = &start
+	}
+	if task.stopSignal == nil {
+		task.stopSignal = make(chan bool)
+	}
+	if task.context == nil {
+		task.context = make(map[string]interface{})
+	}
+	if task.ctx == nil {
+		task.ctx, task.cancel = newTaskContext()
+	}
+	task.heapIndex = -1
 }
 
-// StopTask encapsulates task stopping sequence.
+// StopTask encapsulates task stopping sequence. It cancels context of the
+// running execution, if any.
 func (scheduler *Scheduler) StopTask(task *Task) error {
+	if task.cancel != nil {
+		task.cancel()
+	}
+	task.closeStopSignal()
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	if err := scheduler.removeTask(task); err != nil {
+		return err
+	}
+	scheduler.emit(EventTaskRemoved, task, nil)
+	return nil
+}
+
+// setOwner sets the Scheduler that fires the task, it shall be called with the
+// scheduler mutex locked.
+func (task *Task) setOwner(scheduler *Scheduler) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	task.scheduler = scheduler
+}
+
+// owner returns the Scheduler that fires the task or nil if the task is not
+// scheduled. Unlike the scheduler field, it could be called without the
+// scheduler mutex.
+func (task *Task) owner() *Scheduler {
+	task.mutex.RLock()
+	defer task.mutex.RUnlock()
+	return task.scheduler
+}
+
+// closeStopSignal closes stop signal of the task, so Task.Wait returns.
+func (task *Task) closeStopSignal() {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
 	if task.stopSignal != nil {
 		close(task.stopSignal)
 		task.stopSignal = nil
 	}
-	return scheduler.removeTask(task)
 }
 
-// removeTask removes Task from the tasks list of the Scheduler.
+// removeTask removes Task from the tasks list of the Scheduler and from the
+// timing loop, the last task of the list takes its place. It shall be called
+// with the scheduler mutex locked.
 func (scheduler *Scheduler) removeTask(scheduledTask *Task) error {
-	taskIndex := scheduler.FindTaskIndex(scheduledTask)
-	if taskIndex == -1 {
-		return fmt.Errorf("task with id: %s cannot be stopped, because it was not found", scheduledTask.ID)
+	if scheduledTask.scheduler == scheduler {
+		scheduler.removeTimer(scheduledTask)
+		scheduledTask.setOwner(nil)
 	}
-	if len(scheduler.Tasks) <= 1 {
-		scheduler.Tasks = make([]*Task, 0)
-	} else {
-		scheduler.Tasks = append(scheduler.Tasks[:taskIndex], scheduler.Tasks[taskIndex+1:]...)
+
+	if !scheduler.deleteTask(scheduledTask) {
+		return fmt.Errorf("task with id: %s cannot be stopped, because it was not found", scheduledTask.ID)
 	}
 	return nil
 }
 
-// callFunction calls a function dynamically using reflection.
-// It panics if the function call is not valid.
-func callFunction(function interface{}, parameters ...interface{}) {
+// execute runs the function once within the provided context and records the
+// execution in the task history, afterwards it runs chained tasks. It returns
+// an error returned by the function.
+func (task *Task) execute(ctx context.Context, function interface{}, parameters ...interface{}) error {
+	return task.executeScheduled(ctx, runPlan{}, function, parameters...)
+}
+
+// executeScheduled runs the function in the same way as Task.execute, the run
+// is recorded according to the provided plan.
+func (task *Task) executeScheduled(ctx context.Context, plan runPlan, function interface{}, parameters ...interface{}) error {
+	run := task.startRun(plan)
+	plan.scheduler.emit(EventRunStarted, task, run)
+
+	var tracing, middlewares []Middleware
+	if plan.scheduler != nil {
+		tracing = []Middleware{TracingMiddleware(plan.scheduler.tracer)}
+		middlewares = plan.scheduler.middlewares
+	}
+	job := chainJob(functionJob(function, parameters...), tracing, middlewares, task.Middlewares)
+
+	runCtx := contextWithScheduler(contextWithRun(ctx, run), plan.scheduler)
+	result, err := job(plan.scheduler.contextWithLogger(runCtx, task, run), task)
+	finished := task.finishRun(run, result, err)
+	switch finished.Status {
+	case RunFailed:
+		plan.scheduler.emit(EventRunFailed, task, &finished)
+	case RunSkipped:
+		plan.scheduler.emit(EventRunSkipped, task, &finished)
+	default:
+		plan.scheduler.emit(EventRunSucceeded, task, &finished)
+	}
+	plan.handle.finish(finished)
+
+	task.runChains(ctx, run, plan.scheduler)
+	return err
+}
+
+// contextType is used to check whether the function accepts context.Context.
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// errorType is used to check whether the function returns an error.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// callFunction calls a function dynamically using reflection. If the first
+// parameter of the function is context.Context, then provided context is passed
+// before other parameters. It returns the first value returned by the function
+// that is not an error, and an error if the last value returned by the function
+// is a non-nil error. It panics if the function call is not valid.
+func callFunction(ctx context.Context, function interface{}, parameters ...interface{}) (interface{}, error) {
 	functionReflection := reflect.ValueOf(function)
 	if functionReflection.Kind() != reflect.Func {
 		panic("provided argument is not a function")
 	}
+	functionType := functionReflection.Type()
+
+	if functionType.NumIn() > 0 && functionType.In(0) == contextType {
+		parameters = append([]interface{}{ctx}, parameters...)
+	}
 
 	// Prepare parameters for reflection call.
 	parametersReflection := make([]reflect.Value, len(parameters))
@@ -236,27 +554,65 @@ func callFunction(function interface{}, parameters ...interface{}) {
 	}
 
 	// Call the function with the parameters.
-	functionReflection.Call(parametersReflection)
+	results := functionReflection.Call(parametersReflection)
+
+	var result interface{}
+	var err error
+	for index, value := range results {
+		if functionType.Out(index) == errorType {
+			if index == len(results)-1 {
+				err, _ = value.Interface().(error)
+			}
+			continue
+		}
+		if result == nil && !isNilValue(value) {
+			result = value.Interface()
+		}
+	}
+	return result, err
+}
+
+// isNilValue checks whether reflected value is nil, for the kinds that could
+// be nil.
+func isNilValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return value.IsNil()
+	default:
+		return false
+	}
 }
 
 // GetFromContext receive value for the provided key from the task context.
 func (task *Task) GetFromContext(name string) interface{} {
+	task.mutex.RLock()
+	defer task.mutex.RUnlock()
 	return task.context[name]
 }
 
 // SetToContext adds key-value pair to the task context.
 func (task *Task) SetToContext(name string, value interface{}) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	if task.context == nil {
+		task.context = make(map[string]interface{})
+	}
 	task.context[name] = value
 }
 
 // RemoveFromContext deletes value by key from the task context.
 func (task *Task) RemoveFromContext(name string) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
 	delete(task.context, name)
 }
 
 // Wait waits until task will be completed, either by timer or stop signal, whichever happens first.
 func (task *Task) Wait() {
-	if task.stopSignal != nil {
-		<-task.stopSignal
+	task.mutex.RLock()
+	stopSignal := task.stopSignal
+	task.mutex.RUnlock()
+	if stopSignal != nil {
+		<-stopSignal
 	}
 }
@@ -2,9 +2,12 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -171,18 +174,20 @@ func TestTask_String(t *testing.T) {
 	newTask := NewTask(id, name, &startTime, &duration, interval, stopSignal, context)
 
 	expectedString := fmt.Sprintf(
-		"ID: %s\nName: %s\nStart: %s\nDuration: %s\nInterval: %s\nContext: %v",
+		"ID: %s\nName: %s\nSchedule: %s\nContext: %v",
 		newTask.ID,
 		newTask.Name,
-		newTask.Start.String(),
-		newTask.Duration.String(),
-		newTask.Interval.String(),
+		"every second, starting 2000-01-01 01:02:03, until 2000-01-01 01:02:13",
 		newTask.context,
 	)
 
 	if newTask.String() != expectedString {
 		t.Fatalf("Incorrect string for the task object. Expected: %s. Actual: %s.", expectedString, newTask.String())
 	}
+
+	if description := NewSimpleTask(name, 0).String(); !strings.Contains(description, "Schedule: once at ") {
+		t.Fatalf("Incorrect string for the task without start and duration: %s.", description)
+	}
 }
 
 // TestScheduler_FindTaskIndex tests that Scheduler.FindTaskIndex method returns
@@ -382,3 +387,61 @@ func TestTask_RemoveFromContext(t *testing.T) {
 		t.Fatalf("Task value has not been removed from the context. Value: %v.", contextValue)
 	}
 }
+
+// TestScheduler_ScheduleTask_History tests that Scheduler.ScheduleTask records
+// every execution in the task history, passes execution context to the
+// function that accepts it and marks executions that returned an error as
+// failed.
+func TestScheduler_ScheduleTask_History(t *testing.T) {
+	var testFunction = func(ctx context.Context, task *Task, message string) error {
+		if RunFromContext(ctx).Number%2 == 0 {
+			return errors.New(message)
+		}
+		return nil
+	}
+
+	duration := 250 * time.Millisecond
+
+	newScheduler := CreateEmptyScheduler()
+	newTask := newScheduler.ScheduleTask("Test Task", nil, &duration, 100*time.Millisecond, testFunction, "even run")
+	newTask.Wait()
+
+	history := newTask.History()
+	if len(history) != 3 {
+		t.Fatalf("Incorrect number of runs in the history. Expected: 3. Actual: %d.", len(history))
+	}
+
+	for index, run := range history {
+		expectedStatus := RunSucceeded
+		if run.Number%2 == 0 {
+			expectedStatus = RunFailed
+		}
+		if run.Number != index+1 || run.Status != expectedStatus || run.Finished.IsZero() {
+			t.Fatalf("Run has been recorded incorrectly: %+v.", run)
+		}
+	}
+
+	if history[1].Err == nil || history[1].Err.Error() != "even run" {
+		t.Fatalf("Incorrect error of the failed run: %v.", history[1].Err)
+	}
+}
+
+// TestCallFunction_Result tests that callFunction returns the first non-error
+// value and the last error returned by the function.
+func TestCallFunction_Result(t *testing.T) {
+	result, err := callFunction(context.Background(), func(value int) (int, error) {
+		return value * 2, errors.New("failure")
+	}, 21)
+
+	if result != 42 || err == nil {
+		t.Fatalf("Incorrect function output. Result: %v. Error: %v.", result, err)
+	}
+
+	result, err = callFunction(context.Background(), func() (*Task, error) {
+		return nil, nil
+	})
+
+	if result != nil || err != nil {
+		t.Fatalf("Nil output has been returned incorrectly. Result: %v. Error: %v.", result, err)
+	}
+}
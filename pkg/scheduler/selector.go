@@ -0,0 +1,297 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TagKey is the reserved key that refers to Task.Tags instead of a label in the
+// Selector, e.g. "tag=critical" matches tasks with the "critical" tag.
+const TagKey = "tag"
+
+// SelectorOperator defines how the Requirement compares the label.
+type SelectorOperator string
+
+const (
+	// Equals matches tasks with the label equal to the value.
+	Equals SelectorOperator = "="
+	// NotEquals matches tasks without the label or with a different value.
+	NotEquals SelectorOperator = "!="
+	// In matches tasks with the label equal to one of the values.
+	In SelectorOperator = "in"
+	// NotIn matches tasks without the label or with a value not in the values.
+	NotIn SelectorOperator = "notin"
+	// Exists matches tasks that have the label.
+	Exists SelectorOperator = "exists"
+	// NotExists matches tasks that don't have the label.
+	NotExists SelectorOperator = "!"
+)
+
+// Requirement is a single condition of the Selector.
+type Requirement struct {
+	// Key is the label key or TagKey.
+	Key string
+	// Operator defines how the label is compared.
+	Operator SelectorOperator
+	// Values stores the compared values, Equals and NotEquals use only the first
+	// value, Exists and NotExists don't use them.
+	Values []string
+}
+
+// Selector selects tasks by their labels and tags, task matches the selector if
+// it matches all requirements. Empty selector matches all tasks.
+type Selector []Requirement
+
+// ParseSelector parses selector from the comma-separated list of requirements:
+//
+//	environment=production      label equals value ("==" is accepted as well)
+//	environment!=staging        label is missing or has a different value
+//	tier in (web, api)          label equals one of the values
+//	tier notin (batch)          label is missing or not one of the values
+//	owner                       label exists
+//	!deprecated                 label doesn't exist
+//
+// Key TagKey refers to the tags of the task, e.g. "tag=critical" or
+// "tag notin (experimental)".
+func ParseSelector(text string) (Selector, error) {
+	selector := Selector{}
+	for _, part := range splitSelector(text) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		requirement, err := parseRequirement(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", text, err)
+		}
+		selector = append(selector, requirement)
+	}
+	return selector, nil
+}
+
+// splitSelector splits selector by commas that are not inside parentheses.
+func splitSelector(text string) []string {
+	parts := make([]string, 0)
+	depth, start := 0, 0
+	for index, character := range text {
+		switch character {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, text[start:index])
+				start = index + 1
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// parseRequirement parses a single requirement of the selector.
+func parseRequirement(text string) (Requirement, error) {
+	if strings.HasPrefix(text, "!") && !strings.ContainsAny(text, "=()") {
+		return newRequirement(strings.TrimSpace(text[1:]), NotExists, nil)
+	}
+
+	for _, operator := range []string{"!=", "==", "="} {
+		if key, value, found := strings.Cut(text, operator); found {
+			resolved := Equals
+			if operator == "!=" {
+				resolved = NotEquals
+			}
+			return newRequirement(strings.TrimSpace(key), resolved, []string{strings.TrimSpace(value)})
+		}
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) == 1 {
+		return newRequirement(fields[0], Exists, nil)
+	}
+
+	open := strings.Index(text, "(")
+	if open == -1 || !strings.HasSuffix(text, ")") {
+		return Requirement{}, fmt.Errorf("cannot parse requirement %q", text)
+	}
+	head := strings.Fields(text[:open])
+	if len(head) != 2 || (head[1] != string(In) && head[1] != string(NotIn)) {
+		return Requirement{}, fmt.Errorf("cannot parse requirement %q", text)
+	}
+	values := make([]string, 0)
+	for _, value := range strings.Split(text[open+1:len(text)-1], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return newRequirement(head[0], SelectorOperator(head[1]), values)
+}
+
+// newRequirement creates the requirement and validates it.
+func newRequirement(key string, operator SelectorOperator, values []string) (Requirement, error) {
+	if key == "" || strings.ContainsAny(key, " !=(),") {
+		return Requirement{}, fmt.Errorf("invalid key %q", key)
+	}
+	if (operator == In || operator == NotIn) && len(values) == 0 {
+		return Requirement{}, fmt.Errorf("operator %s requires at least one value", operator)
+	}
+	return Requirement{Key: key, Operator: operator, Values: values}, nil
+}
+
+// String returns the selector in the format accepted by ParseSelector.
+func (selector Selector) String() string {
+	parts := make([]string, len(selector))
+	for index, requirement := range selector {
+		parts[index] = requirement.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// String returns the requirement in the format accepted by ParseSelector.
+func (requirement Requirement) String() string {
+	switch requirement.Operator {
+	case Exists:
+		return requirement.Key
+	case NotExists:
+		return "!" + requirement.Key
+	case In, NotIn:
+		return fmt.Sprintf("%s %s (%s)", requirement.Key, requirement.Operator, strings.Join(requirement.Values, ","))
+	default:
+		return requirement.Key + string(requirement.Operator) + requirement.value()
+	}
+}
+
+// value returns the first value of the requirement.
+func (requirement Requirement) value() string {
+	if len(requirement.Values) == 0 {
+		return ""
+	}
+	return requirement.Values[0]
+}
+
+// Matches checks whether the task matches all requirements of the selector.
+func (selector Selector) Matches(task *Task) bool {
+	for _, requirement := range selector {
+		if !requirement.Matches(task) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches checks whether the task matches the requirement.
+func (requirement Requirement) Matches(task *Task) bool {
+	var values []string
+	if requirement.Key == TagKey {
+		values = task.Tags
+	} else if value, ok := task.Labels[requirement.Key]; ok {
+		values = []string{value}
+	}
+
+	switch requirement.Operator {
+	case Equals:
+		return containsAny(values, requirement.value())
+	case NotEquals:
+		return !containsAny(values, requirement.value())
+	case In:
+		return containsAny(values, requirement.Values...)
+	case NotIn:
+		return !containsAny(values, requirement.Values...)
+	case Exists:
+		return len(values) > 0
+	case NotExists:
+		return len(values) == 0
+	}
+	return false
+}
+
+// containsAny checks whether values contain any of the expected values.
+func containsAny(values []string, expected ...string) bool {
+	for _, value := range values {
+		for _, candidate := range expected {
+			if value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasTag checks whether the task has provided tag.
+func (task *Task) HasTag(tag string) bool {
+	return containsAny(task.Tags, tag)
+}
+
+// FindTasks returns all scheduled tasks that match the selector.
+func (scheduler *Scheduler) FindTasks(selector Selector) []*Task {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return scheduler.findTasks(selector)
+}
+
+// findTasks returns all scheduled tasks that match the selector, it shall be
+// called with the scheduler mutex locked.
+func (scheduler *Scheduler) findTasks(selector Selector) []*Task {
+	tasks := make([]*Task, 0)
+	for _, task := range scheduler.Tasks {
+		if selector.Matches(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// StopTasks stops all scheduled tasks that match the selector and returns
+// them.
+func (scheduler *Scheduler) StopTasks(selector Selector) ([]*Task, error) {
+	tasks := scheduler.FindTasks(selector)
+	var problems []error
+	for _, task := range tasks {
+		if err := scheduler.StopTask(task); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return tasks, errors.Join(problems...)
+}
+
+// PauseTasks pauses all scheduled tasks that match the selector and returns
+// them, see Scheduler.PauseTask.
+func (scheduler *Scheduler) PauseTasks(selector Selector) []*Task {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	tasks := scheduler.findTasks(selector)
+	for _, task := range tasks {
+		_ = scheduler.pause(task)
+	}
+	return tasks
+}
+
+// ResumeTasks resumes all scheduled tasks that match the selector and returns
+// them, see Scheduler.ResumeTask.
+func (scheduler *Scheduler) ResumeTasks(selector Selector) []*Task {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	now := time.Now()
+	tasks := scheduler.findTasks(selector)
+	for _, task := range tasks {
+		_ = scheduler.resume(task, now)
+	}
+	return tasks
+}
+
+// TriggerTasks queues an immediate run of all scheduled tasks that match the
+// selector and returns them. The regular fire times of the tasks are not
+// affected. Runs are queued and skipped the same way as by
+// Scheduler.TriggerTask.
+func (scheduler *Scheduler) TriggerTasks(selector Selector) []*Task {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	now := time.Now()
+	tasks := scheduler.findTasks(selector)
+	for _, task := range tasks {
+		_, _ = scheduler.trigger(task, now, triggerRequest{})
+	}
+	return tasks
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// CreateLabelledTask creates a task with provided tags and labels.
+func CreateLabelledTask(name string, interval time.Duration, tags []string, labels map[string]string) *Task {
+	task := NewTask("", name, nil, nil, interval, nil, nil)
+	task.Tags = tags
+	task.Labels = labels
+	return task
+}
+
+// TestParseSelector tests that ParseSelector parses all supported operators and
+// rejects invalid selectors.
+func TestParseSelector(t *testing.T) {
+	selector, err := ParseSelector("env==prod, tier in (web, api),team!=ops,tag notin (slow),owner,!legacy")
+	if err != nil {
+		t.Fatalf("Selector has not been parsed. Error: %v.", err)
+	}
+
+	expected := "env=prod,tier in (web,api),team!=ops,tag notin (slow),owner,!legacy"
+	if selector.String() != expected {
+		t.Fatalf("Incorrect selector. Expected: %s. Actual: %s.", expected, selector.String())
+	}
+
+	for _, text := range []string{"tier in ()", "tier between (a,b)", "=prod", "tier in (a"} {
+		if _, err = ParseSelector(text); err == nil {
+			t.Fatalf("Invalid selector %q has been parsed.", text)
+		}
+	}
+}
+
+// TestSelector_Matches tests that Selector matches tasks by labels and tags.
+func TestSelector_Matches(t *testing.T) {
+	task := CreateLabelledTask("Task", time.Minute, []string{"critical"}, map[string]string{"env": "prod", "tier": "web"})
+
+	cases := map[string]bool{
+		"":                            true,
+		"env=prod":                    true,
+		"env=staging":                 false,
+		"env!=staging":                true,
+		"tier in (web,api)":           true,
+		"tier notin (web)":            false,
+		"owner":                       false,
+		"!owner":                      true,
+		"tag=critical":                true,
+		"tag!=critical":               false,
+		"tag in (slow,critical)":      true,
+		"env=prod,tag=critical,!team": true,
+		"env=prod,tag=slow":           false,
+	}
+	for text, expected := range cases {
+		selector, err := ParseSelector(text)
+		if err != nil {
+			t.Fatalf("Selector %q has not been parsed. Error: %v.", text, err)
+		}
+		if selector.Matches(task) != expected {
+			t.Fatalf("Incorrect match of the selector %q. Expected: %v. Actual: %v.", text, expected, !expected)
+		}
+	}
+}
+
+// TestScheduler_FindTasks tests that Scheduler.FindTasks returns all tasks that
+// match the selector and Scheduler.StopTasks stops them.
+func TestScheduler_FindTasks(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	for _, env := range []string{"prod", "prod", "staging"} {
+		_ = newScheduler.Schedule(CreateLabelledTask("Task", time.Hour, nil, map[string]string{"env": env}), func(task *Task) {})
+	}
+
+	selector, _ := ParseSelector("env=prod")
+	if tasks := newScheduler.FindTasks(selector); len(tasks) != 2 {
+		t.Fatalf("Incorrect number of found tasks. Expected: 2. Actual: %d.", len(tasks))
+	}
+
+	stopped, err := newScheduler.StopTasks(selector)
+	if err != nil || len(stopped) != 2 {
+		t.Fatalf("Tasks have not been stopped: %d. Error: %v.", len(stopped), err)
+	}
+	for _, task := range stopped {
+		task.Wait()
+	}
+
+	if len(newScheduler.Tasks) != 1 || newScheduler.Tasks[0].Labels["env"] != "staging" {
+		t.Fatalf("Incorrect tasks left in the scheduler: %v.", newScheduler.Tasks)
+	}
+}
+
+// TestScheduler_PauseTasks tests that paused tasks are not fired until they are
+// resumed.
+func TestScheduler_PauseTasks(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	paused := CreateLabelledTask("Paused", 20*time.Millisecond, []string{"batch"}, nil)
+	running := CreateLabelledTask("Running", 20*time.Millisecond, nil, nil)
+	_ = newScheduler.Schedule(paused, func(task *Task) {})
+	_ = newScheduler.Schedule(running, func(task *Task) {})
+
+	selector := Selector{{Key: TagKey, Operator: Equals, Values: []string{"batch"}}}
+	if tasks := newScheduler.PauseTasks(selector); len(tasks) != 1 || !paused.Paused() {
+		t.Fatalf("Task has not been paused.")
+	}
+	time.Sleep(30 * time.Millisecond)
+	runs := len(paused.History())
+	time.Sleep(100 * time.Millisecond)
+
+	if len(paused.History()) != runs {
+		t.Fatalf("Paused task has been fired: %v.", paused.History())
+	}
+	if CountRuns(running, RunSucceeded) < 3 {
+		t.Fatalf("Not paused task has not been fired: %v.", running.History())
+	}
+
+	newScheduler.ResumeTasks(selector)
+	time.Sleep(100 * time.Millisecond)
+	if paused.Paused() || len(paused.History()) <= runs {
+		t.Fatalf("Resumed task has not been fired: %v.", paused.History())
+	}
+}
+
+// TestScheduler_TriggerTasks tests that Scheduler.TriggerTasks runs matching
+// tasks immediately without affecting their schedule.
+func TestScheduler_TriggerTasks(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	task := CreateLabelledTask("Report", time.Hour, nil, map[string]string{"kind": "report"})
+	task.Start = &start
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+
+	selector, _ := ParseSelector("kind=report")
+	if tasks := newScheduler.TriggerTasks(selector); len(tasks) != 1 {
+		t.Fatalf("Incorrect number of triggered tasks. Expected: 1. Actual: %d.", len(tasks))
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if CountRuns(task, RunSucceeded) != 1 {
+		t.Fatalf("Task has not been triggered: %v.", task.History())
+	}
+	if !task.nextFire.Equal(start) {
+		t.Fatalf("Schedule of the task has been changed: %s.", task.nextFire)
+	}
+}
@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"time"
+)
+
+// once checks whether the task is fired only once.
+func (task *Task) once() bool {
+	return task.cron == nil && task.Interval <= 0
+}
+
+// firstFireTime returns the first planned fire time at or after the start
+// time that is allowed by the calendar, zero time if the task is never fired.
+// It is in the Location of the task, if any.
+func (task *Task) firstFireTime(start time.Time) time.Time {
+	start = task.inLocation(start)
+	first := start
+	if task.cron != nil {
+		first = task.cron.Next(start.Add(-time.Nanosecond))
+	}
+	if first.IsZero() || task.admits(first, time.Time{}) {
+		return first
+	}
+	return task.nextAdmitted(first, time.Time{}, time.Time{})
+}
+
+// nextPlanned returns the first planned fire time after the provided one that
+// is not in the past and is allowed by the calendar, missed fire times are
+// skipped. It returns zero time if the task is not fired anymore.
+func (task *Task) nextPlanned(planned time.Time, now time.Time) time.Time {
+	return task.nextAdmitted(planned, planned, now)
+}
+
+// nextAdmitted returns the first fire time after the candidate that is not in
+// the past and is allowed by the calendar after the previous fire time. It
+// returns zero time if there is no such time within the calendar horizon.
+func (task *Task) nextAdmitted(candidate time.Time, previous time.Time, now time.Time) time.Time {
+	next := task.nextScheduled(candidate, now)
+	for !next.IsZero() && !task.admits(next, previous) {
+		if next.Sub(candidate) > calendarHorizon {
+			return time.Time{}
+		}
+		// Fire times that could not be admitted are skipped at once: the
+		// calendar excludes the whole day, and shifted fire times are admitted
+		// only after the previous shifted one.
+		skip := now
+		if task.CalendarPolicy == CalendarShift {
+			if task.calendarTime(next).IsZero() {
+				return time.Time{}
+			}
+			if shifted := task.calendarTime(previous); shifted.After(skip) {
+				skip = shifted
+			}
+		} else {
+			year, month, day := next.Date()
+			if midnight := time.Date(year, month, day+1, 0, 0, 0, 0, next.Location()).Add(-time.Nanosecond); midnight.After(skip) {
+				skip = midnight
+			}
+		}
+		next = task.nextScheduled(next, skip)
+	}
+	return next
+}
+
+// nextScheduled returns the first fire time of the interval or the cron
+// expression after the provided one that is not in the past, regardless of
+// the calendar. Cron expressions are evaluated in the Location of the task.
+func (task *Task) nextScheduled(planned time.Time, now time.Time) time.Time {
+	planned = task.inLocation(planned)
+	if task.cron == nil {
+		if task.Interval <= 0 {
+			return time.Time{}
+		}
+		if task.intervalDays() > 0 {
+			return task.nextDay(planned, now)
+		}
+		return nextFireTime(planned, task.Interval, now)
+	}
+	next := task.cron.Next(planned)
+	if !next.IsZero() && next.Before(now) {
+		next = task.cron.Next(task.inLocation(now))
+	}
+	return next
+}
+
+// maxMissed limits how many missed cron fire times are counted.
+const maxMissed = 1000
+
+// missed returns the number of planned fire times between the fired one and
+// the next one that have been skipped by Task.nextPlanned.
+func (task *Task) missed(fired time.Time, next time.Time) int {
+	if next.IsZero() || task.once() {
+		return 0
+	}
+	if task.cron == nil && task.Calendar == nil && task.intervalDays() == 0 {
+		return int(next.Sub(fired)/task.Interval) - 1
+	}
+	missed := 0
+	for planned := task.nextPlanned(fired, time.Time{}); !planned.IsZero() && planned.Before(next) && missed < maxMissed; planned = task.nextPlanned(planned, time.Time{}) {
+		missed++
+	}
+	return missed
+}
+
+// jitter returns the delay added to the planned fire time, it is between zero
+// and Task.Jitter. The delay is derived from the planned time and the jitter
+// seed, so it is the same every time it is calculated for the same fire time.
+func (task *Task) jitter(planned time.Time) time.Duration {
+	if task.Jitter <= 0 {
+		return 0
+	}
+	hash := fnv.New64a()
+	if task.JitterSeed != 0 {
+		_ = binary.Write(hash, binary.LittleEndian, task.JitterSeed)
+	} else {
+		_, _ = hash.Write([]byte(task.ID))
+	}
+	_ = binary.Write(hash, binary.LittleEndian, planned.UnixNano())
+	return time.Duration(hash.Sum64() % uint64(task.Jitter))
+}
+
+// scheduledTime returns the time when the planned run shall be fired, that is
+// the planned time moved according to the calendar and delayed by the jitter.
+func (task *Task) scheduledTime() time.Time {
+	fire := task.calendarTime(task.planned)
+	return fire.Add(task.jitter(fire))
+}
+
+// NextRuns returns up to count times when the task is fired at or after
+// provided time, according to its start time, Duration, Interval or Cron,
+// Calendar, Jitter and Location. If the start time is not set, the task is
+// assumed to start at provided time. The task does not have to be scheduled,
+// but the task without ID and JitterSeed gets different jitter once it is
+// scheduled, because its ID is generated. Paused tasks and triggered runs are
+// not taken into account. It returns an empty slice if count is not positive
+// and an error if the Cron is not valid.
+func (task *Task) NextRuns(after time.Time, count int) ([]time.Time, error) {
+	preview, err := task.preview()
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		return []time.Time{}, nil
+	}
+	start := after
+	if preview.Start != nil {
+		start = *preview.Start
+	}
+	preview.Start = &start
+	end := time.Time{}
+	if preview.Duration != nil {
+		end = start.Add(*preview.Duration)
+	}
+
+	runs := make([]time.Time, 0, count)
+	planned := preview.firstFireTime(start)
+	if skip := after.Add(-preview.Jitter); !planned.IsZero() && planned.Before(skip) {
+		planned = preview.nextPlanned(planned, skip)
+	}
+	for ; !planned.IsZero() && len(runs) < count; planned = preview.nextPlanned(planned, time.Time{}) {
+		fireTime := preview.calendarTime(planned)
+		if !end.IsZero() && !fireTime.Before(end) {
+			break
+		}
+		if fireTime = fireTime.Add(preview.jitter(fireTime)); !fireTime.Before(after) {
+			runs = append(runs, fireTime)
+		}
+	}
+	return runs, nil
+}
+
+// preview returns the copy of the schedule of the task with the parsed Cron,
+// it is used to calculate fire times without changing the task.
+func (task *Task) preview() (*Task, error) {
+	task.mutex.RLock()
+	preview := &Task{
+		ID:             task.ID,
+		Start:          task.Start,
+		Duration:       task.Duration,
+		Interval:       task.Interval,
+		Cron:           task.Cron,
+		Jitter:         task.Jitter,
+		JitterSeed:     task.JitterSeed,
+		Calendar:       task.Calendar,
+		CalendarPolicy: task.CalendarPolicy,
+		Location:       task.Location,
+	}
+	task.mutex.RUnlock()
+	return preview, preview.parseCron()
+}
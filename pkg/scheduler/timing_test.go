@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTask_Jitter tests that jitter is within the configured range and the same
+// for the same fire time and seed.
+func TestTask_Jitter(t *testing.T) {
+	task := &Task{ID: "task", Jitter: time.Minute}
+	other := &Task{ID: "task", Jitter: time.Minute, JitterSeed: 42}
+	planned := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	different := false
+	for index := 0; index < 100; index++ {
+		fireTime := planned.Add(time.Duration(index) * time.Hour)
+		jitter := task.jitter(fireTime)
+		if jitter < 0 || jitter >= time.Minute {
+			t.Fatalf("Jitter is out of range: %s.", jitter)
+		}
+		if jitter != task.jitter(fireTime) {
+			t.Fatalf("Jitter is not deterministic for %s.", fireTime)
+		}
+		different = different || jitter != other.jitter(fireTime)
+	}
+
+	if !different {
+		t.Fatalf("Jitter doesn't depend on the seed.")
+	}
+	if jitter := (&Task{}).jitter(planned); jitter != 0 {
+		t.Fatalf("Jitter has been added to the task without jitter: %s.", jitter)
+	}
+}
+
+// TestTask_NextPlanned tests that next planned fire time follows the interval or
+// the cron expression and skips missed fire times.
+func TestTask_NextPlanned(t *testing.T) {
+	planned := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	task := &Task{Interval: time.Minute}
+	if next := task.nextPlanned(planned, planned.Add(150*time.Second)); !next.Equal(planned.Add(3 * time.Minute)) {
+		t.Fatalf("Incorrect next fire time of the interval task: %s.", next)
+	}
+
+	task.cron, _ = ParseCron("0 * * * *")
+	if next := task.nextPlanned(planned, planned.Add(150*time.Minute)); !next.Equal(planned.Add(3 * time.Hour)) {
+		t.Fatalf("Incorrect next fire time of the cron task: %s.", next)
+	}
+
+	if next := (&Task{}).nextPlanned(planned, planned); !next.IsZero() {
+		t.Fatalf("Task that runs once has next fire time: %s.", next)
+	}
+}
+
+// TestTask_NextRuns tests that the fire times of the task that is not
+// scheduled follow its start, end, calendar, jitter and location.
+func TestTask_NextRuns(t *testing.T) {
+	friday := time.Date(2024, time.December, 20, 9, 0, 0, 0, time.UTC)
+	duration := 7 * 24 * time.Hour
+	task := NewTask("", "Task", &friday, &duration, 24*time.Hour, nil, nil)
+	task.Calendar = BusinessDays
+
+	runs, err := task.NextRuns(friday.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Fire times have not been returned. Error: %v.", err)
+	}
+	expected := []time.Time{friday.AddDate(0, 0, 3), friday.AddDate(0, 0, 4), friday.AddDate(0, 0, 5), friday.AddDate(0, 0, 6)}
+	if len(runs) != len(expected) {
+		t.Fatalf("Incorrect fire times: %v.", runs)
+	}
+	for index := range expected {
+		if !runs[index].Equal(expected[index]) {
+			t.Fatalf("Incorrect fire times: %v.", runs)
+		}
+	}
+
+	task.JitterSeed, task.Jitter = 1, time.Minute
+	jittered, _ := task.NextRuns(friday, 2)
+	if len(jittered) != 2 || jittered[0].Sub(friday) != task.jitter(friday) || jittered[0].Sub(friday) >= time.Minute {
+		t.Fatalf("Incorrect jittered fire times: %v.", jittered)
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	task = &Task{Cron: "0 9 * * *", Location: berlin}
+	now := time.Date(2024, time.March, 30, 12, 0, 0, 0, berlin)
+	if runs, _ = task.NextRuns(now, 2); len(runs) != 2 || !runs[1].Equal(time.Date(2024, time.April, 1, 9, 0, 0, 0, berlin)) {
+		t.Fatalf("Incorrect fire times in the location: %v.", runs)
+	}
+	if task.cron != nil {
+		t.Fatalf("Task has been changed by the preview.")
+	}
+
+	if runs, err = task.NextRuns(now, -1); err != nil || len(runs) != 0 {
+		t.Fatalf("Fire times have been returned for the negative count: %v, %v.", runs, err)
+	}
+
+	task.Cron = "invalid"
+	if _, err = task.NextRuns(now, 1); err == nil {
+		t.Fatalf("Invalid cron expression has not been reported.")
+	}
+}
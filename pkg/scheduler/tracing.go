@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Span attributes set on the root span of every execution.
+const (
+	AttributeTaskID       = "task.id"
+	AttributeTaskName     = "task.name"
+	AttributeRunAttempt   = "run.attempt"
+	AttributeRunScheduled = "run.scheduled"
+	AttributeRunTriggered = "run.triggered"
+	AttributeRunSkipped   = "run.skipped"
+	AttributeRunPanicked  = "run.panicked"
+)
+
+// Attribute is a key-value pair attached to the Span.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span represents a traced operation.
+type Span interface {
+	// SetAttributes adds attributes to the span.
+	SetAttributes(attributes ...Attribute)
+	// RecordError records the error of the operation.
+	RecordError(err error)
+	// End finishes the span.
+	End()
+}
+
+// Tracer starts spans, it could be implemented using the tracing library of
+// choice, e.g. as an adapter for OpenTelemetry.
+type Tracer interface {
+	// Start starts the span as a child of the span in the context, if any, and
+	// returns the context that carries the new span.
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+// WithTracer makes the Scheduler open a span for every execution, it wraps
+// all middlewares. NoopTracer is used by default.
+func WithTracer(tracer Tracer) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.tracer = tracer
+	}
+}
+
+// TracingMiddleware opens the span around the execution with the attributes of
+// the task and the run, the span is available to the function through the
+// context. The error of the function and its panic are recorded, the panic is
+// propagated afterwards.
+func TracingMiddleware(tracer Tracer) Middleware {
+	return func(next Job) Job {
+		return func(ctx context.Context, task *Task) (result interface{}, err error) {
+			name := "task run"
+			if task.Name != "" {
+				name += ": " + task.Name
+			}
+			attributes := []Attribute{{Key: AttributeTaskID, Value: task.ID}, {Key: AttributeTaskName, Value: task.Name}}
+			if run := RunFromContext(ctx); run != nil {
+				attributes = append(attributes,
+					Attribute{Key: AttributeRunAttempt, Value: run.Number},
+					Attribute{Key: AttributeRunScheduled, Value: run.Scheduled},
+					Attribute{Key: AttributeRunTriggered, Value: run.Triggered},
+				)
+			}
+
+			ctx, span := tracer.Start(ctx, name, attributes...)
+			defer span.End()
+			defer func() {
+				if value := recover(); value != nil {
+					span.SetAttributes(Attribute{Key: AttributeRunPanicked, Value: true})
+					span.RecordError(&PanicError{Value: value, Stack: debug.Stack()})
+					panic(value)
+				}
+			}()
+
+			result, err = next(ctx, task)
+			if errors.Is(err, ErrSkipped) {
+				span.SetAttributes(Attribute{Key: AttributeRunSkipped, Value: true})
+			} else if err != nil {
+				span.RecordError(err)
+			}
+			return result, err
+		}
+	}
+}
+
+// spanContextKey is the key of the Span in the context.
+type spanContextKey struct{}
+
+// ContextWithSpan returns a copy of the context that carries the span, it
+// could be used by Tracer implementations.
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext returns the span carried by the context, the no-op span is
+// returned if there is none.
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanContextKey{}).(Span); ok {
+		return span
+	}
+	return noopSpan{}
+}
+
+// NoopTracer is the Tracer that doesn't record anything.
+type NoopTracer struct{}
+
+// Start returns the context unchanged and the no-op span.
+func (tracer NoopTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan is the Span that doesn't record anything.
+type noopSpan struct{}
+
+// SetAttributes does nothing.
+func (span noopSpan) SetAttributes(attributes ...Attribute) {}
+
+// RecordError does nothing.
+func (span noopSpan) RecordError(err error) {}
+
+// End does nothing.
+func (span noopSpan) End() {}
+
+// RecordedSpan is the Span recorded by the RecordingTracer.
+type RecordedSpan struct {
+	// ID identifies the span within the tracer, it starts from 1.
+	ID int
+	// ParentID is the ID of the parent span, it is 0 for the root span.
+	ParentID int
+	// Name is the name of the span.
+	Name string
+	// Attributes stores attributes of the span.
+	Attributes map[string]interface{}
+	// Errors stores recorded errors.
+	Errors []error
+	// Started is when the span has been started.
+	Started time.Time
+	// Ended is when the span has been ended, it is zero for the active span.
+	Ended time.Time
+	// tracer guards the span.
+	tracer *RecordingTracer
+}
+
+// SetAttributes adds attributes to the span.
+func (span *RecordedSpan) SetAttributes(attributes ...Attribute) {
+	span.tracer.mutex.Lock()
+	defer span.tracer.mutex.Unlock()
+	for _, attribute := range attributes {
+		span.Attributes[attribute.Key] = attribute.Value
+	}
+}
+
+// RecordError records the error of the span.
+func (span *RecordedSpan) RecordError(err error) {
+	span.tracer.mutex.Lock()
+	defer span.tracer.mutex.Unlock()
+	span.Errors = append(span.Errors, err)
+}
+
+// End finishes the span.
+func (span *RecordedSpan) End() {
+	span.tracer.mutex.Lock()
+	defer span.tracer.mutex.Unlock()
+	if span.Ended.IsZero() {
+		span.Ended = time.Now()
+	}
+}
+
+// RecordingTracer is the Tracer that keeps spans in memory, it is useful in
+// tests.
+type RecordingTracer struct {
+	spans []*RecordedSpan
+	mutex sync.Mutex
+}
+
+// NewRecordingTracer creates an empty RecordingTracer.
+func NewRecordingTracer() *RecordingTracer {
+	return &RecordingTracer{}
+}
+
+// Start starts the span as a child of the recorded span in the context, if
+// any.
+func (tracer *RecordingTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	span := &RecordedSpan{ID: len(tracer.spans) + 1, Name: name, Attributes: make(map[string]interface{}), Started: time.Now(), tracer: tracer}
+	if parent, ok := SpanFromContext(ctx).(*RecordedSpan); ok && parent.tracer == tracer {
+		span.ParentID = parent.ID
+	}
+	for _, attribute := range attributes {
+		span.Attributes[attribute.Key] = attribute.Value
+	}
+	tracer.spans = append(tracer.spans, span)
+	return ContextWithSpan(ctx, span), span
+}
+
+// Spans returns copies of all recorded spans in the order they were started.
+func (tracer *RecordingTracer) Spans() []RecordedSpan {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	spans := make([]RecordedSpan, len(tracer.spans))
+	for index, span := range tracer.spans {
+		spans[index] = *span
+		spans[index].Attributes = make(map[string]interface{}, len(span.Attributes))
+		for key, value := range span.Attributes {
+			spans[index].Attributes[key] = value
+		}
+		spans[index].Errors = append([]error(nil), span.Errors...)
+	}
+	return spans
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestScheduler_Tracer tests that the scheduler opens the root span for the
+// execution with the attributes of the task and the run, the span is
+// propagated to the function and the error is recorded.
+func TestScheduler_Tracer(t *testing.T) {
+	tracer := NewRecordingTracer()
+	newScheduler := New(WithTracer(tracer))
+	defer newScheduler.Shutdown(context.Background())
+
+	failure := errors.New("failure")
+	task := newScheduler.ScheduleTask("Report", nil, nil, 0, func(ctx context.Context, task *Task) error {
+		_, span := tracer.Start(ctx, "query")
+		span.End()
+		return failure
+	})
+	task.Wait()
+
+	spans := tracer.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Incorrect number of spans: %+v.", spans)
+	}
+	root, child := spans[0], spans[1]
+	run, _ := task.LastRun()
+	if root.Name != "task run: Report" || root.ParentID != 0 || root.Ended.IsZero() {
+		t.Fatalf("Incorrect root span: %+v.", root)
+	}
+	if root.Attributes[AttributeTaskID] != task.ID || root.Attributes[AttributeTaskName] != "Report" || root.Attributes[AttributeRunAttempt] != 1 || root.Attributes[AttributeRunScheduled] != run.Scheduled {
+		t.Fatalf("Incorrect attributes of the root span: %v.", root.Attributes)
+	}
+	if len(root.Errors) != 1 || !errors.Is(root.Errors[0], failure) {
+		t.Fatalf("Error has not been recorded: %v.", root.Errors)
+	}
+	if child.ParentID != root.ID {
+		t.Fatalf("Span of the function is not nested under the root span: %+v.", child)
+	}
+}
+
+// TestTracingMiddleware_Panic tests that the panic of the function is recorded
+// and propagated.
+func TestTracingMiddleware_Panic(t *testing.T) {
+	tracer := NewRecordingTracer()
+	job := TracingMiddleware(tracer)(func(ctx context.Context, task *Task) (interface{}, error) {
+		panic("failure")
+	})
+
+	func() {
+		defer func() {
+			if value := recover(); value != "failure" {
+				t.Fatalf("Panic has not been propagated: %v.", value)
+			}
+		}()
+		_, _ = job(context.Background(), &Task{ID: "id"})
+	}()
+
+	spans := tracer.Spans()
+	var panicError *PanicError
+	if len(spans) != 1 || spans[0].Ended.IsZero() || spans[0].Attributes[AttributeRunPanicked] != true {
+		t.Fatalf("Incorrect span of the panicked execution: %+v.", spans)
+	}
+	if len(spans[0].Errors) != 1 || !errors.As(spans[0].Errors[0], &panicError) || panicError.Value != "failure" {
+		t.Fatalf("Panic has not been recorded: %v.", spans[0].Errors)
+	}
+}
+
+// TestNoopTracer tests that the no-op tracer doesn't change the context.
+func TestNoopTracer(t *testing.T) {
+	ctx := context.Background()
+	spanContext, span := NoopTracer{}.Start(ctx, "span")
+	span.SetAttributes(Attribute{Key: "key", Value: "value"})
+	span.RecordError(errors.New("failure"))
+	span.End()
+	if spanContext != ctx {
+		t.Fatalf("Context has been changed by the no-op tracer.")
+	}
+	if _, ok := SpanFromContext(ctx).(noopSpan); !ok {
+		t.Fatalf("No-op span has not been returned for the context without span.")
+	}
+}
@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RunHandle is returned by Scheduler.TriggerTask to await the triggered run.
+type RunHandle struct {
+	// Task is the triggered task.
+	Task *Task
+	// done is closed once the run is finished or skipped.
+	done chan struct{}
+	// run stores the record of the finished run.
+	run Run
+	// once guards closing of done.
+	once sync.Once
+}
+
+// newRunHandle creates a handle of the run of provided task.
+func newRunHandle(task *Task) *RunHandle {
+	return &RunHandle{Task: task, done: make(chan struct{})}
+}
+
+// finish stores the record of the run and releases waiting goroutines.
+func (handle *RunHandle) finish(run Run) {
+	if handle == nil {
+		return
+	}
+	handle.once.Do(func() {
+		handle.run = run
+		close(handle.done)
+	})
+}
+
+// Done returns a channel that is closed once the run is finished or skipped.
+func (handle *RunHandle) Done() <-chan struct{} {
+	return handle.done
+}
+
+// Wait waits until the run is finished or skipped and returns its record, the
+// error is returned if context is done before that.
+func (handle *RunHandle) Wait(ctx context.Context) (Run, error) {
+	select {
+	case <-handle.done:
+		return handle.run, nil
+	case <-ctx.Done():
+		return Run{}, ctx.Err()
+	}
+}
+
+// Result returns the record of the run, the second value is false if the run
+// is not finished yet.
+func (handle *RunHandle) Result() (Run, bool) {
+	select {
+	case <-handle.done:
+		return handle.run, true
+	default:
+		return Run{}, false
+	}
+}
+
+// triggerRequest stores options of the triggered run.
+type triggerRequest struct {
+	parameters    []interface{}
+	hasParameters bool
+	resetTimer    bool
+}
+
+// TriggerOption configures the run triggered by Scheduler.TriggerTask.
+type TriggerOption func(request *triggerRequest)
+
+// TriggerWithParameters replaces the parameters passed to the function after
+// the Task for the triggered run only.
+func TriggerWithParameters(parameters ...interface{}) TriggerOption {
+	return func(request *triggerRequest) {
+		request.parameters = parameters
+		request.hasParameters = true
+	}
+}
+
+// TriggerResetTimer makes the next regular fire time of the task be calculated
+// from the trigger time, e.g. the task with the interval of one hour triggered
+// manually is fired again one hour later.
+func TriggerResetTimer() TriggerOption {
+	return func(request *triggerRequest) {
+		request.resetTimer = true
+	}
+}
+
+// TriggerTask queues an immediate out-of-band run of the scheduled task and
+// returns the handle to await it. The run is recorded with Run.Triggered set
+// and skipped if the task uses OverlapSkip and its previous run is still in
+// progress. It never waits for the free worker: when all workers are busy and
+// the queue is full, the run is queued over the queue size with
+// SaturationBlock, otherwise it is skipped with ErrSchedulerSaturated. The run that exceeds the rate limit of the task is
+// skipped with ErrRateLimited regardless of the throttle policy. The regular
+// fire times are not affected, unless TriggerResetTimer is used.
+func (scheduler *Scheduler) TriggerTask(task *Task, options ...TriggerOption) (*RunHandle, error) {
+	request := triggerRequest{}
+	for _, option := range options {
+		option(&request)
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return scheduler.trigger(task, time.Now(), request)
+}
+
+// TriggerTaskByID triggers the scheduled task with provided ID, see
+// Scheduler.TriggerTask.
+func (scheduler *Scheduler) TriggerTaskByID(id string, options ...TriggerOption) (*RunHandle, error) {
+	task := scheduler.FindTaskByID(id)
+	if task == nil {
+		return nil, fmt.Errorf("task with id: %s cannot be triggered, because it was not found", id)
+	}
+	return scheduler.TriggerTask(task, options...)
+}
+
+// TriggerTaskByName triggers the scheduled task with provided name, see
+// Scheduler.TriggerTask.
+func (scheduler *Scheduler) TriggerTaskByName(name string, options ...TriggerOption) (*RunHandle, error) {
+	task := scheduler.FindTaskByName(name)
+	if task == nil {
+		return nil, fmt.Errorf("task with name: %s cannot be triggered, because it was not found", name)
+	}
+	return scheduler.TriggerTask(task, options...)
+}
+
+// trigger queues an immediate out-of-band run of the task. It shall be called
+// with the scheduler mutex locked.
+func (scheduler *Scheduler) trigger(task *Task, now time.Time, request triggerRequest) (*RunHandle, error) {
+	if task.scheduler != scheduler {
+		return nil, fmt.Errorf("task with id: %s cannot be triggered, because it was not found", task.ID)
+	}
+
+	handle := newRunHandle(task)
+	item := dispatch{task: task, scheduled: now, triggered: true, handle: handle}
+	if request.hasParameters {
+		item.parameters = request.parameters
+		item.hasParameters = true
+	}
+
+	if task.Overlap == OverlapSkip && scheduler.active[task] > 0 {
+		scheduler.skipDispatch(item, ErrTaskRunning)
+	} else if scheduler.admit(item) {
+		scheduler.push(item, time.Now())
+	}
+
+	if request.resetTimer && !task.once() {
+		task.last = now
+		task.planned = task.nextPlanned(now, now)
+		if !task.paused.Load() {
+			scheduler.removeTimer(task)
+			scheduler.reschedule(task)
+		}
+	}
+	return handle, nil
+}
+
+// admit applies the saturation policy and the rate limiters to the triggered
+// run. The run could not be postponed and the caller is never blocked, so it
+// is skipped with ErrSchedulerSaturated or ErrRateLimited instead, only with
+// SaturationBlock the saturated run is queued over the queue size. It returns
+// false if the run has been skipped, it shall be called with the scheduler
+// mutex locked.
+func (scheduler *Scheduler) admit(item dispatch) bool {
+	if scheduler.saturation != SaturationBlock && scheduler.saturated() {
+		scheduler.skipDispatch(item, ErrSchedulerSaturated)
+		return false
+	}
+
+	if wait, _ := scheduler.throttle(item.task, time.Now()); wait > 0 {
+		scheduler.skipDispatch(item, ErrRateLimited)
+		return false
+	}
+	return true
+}
+
+// skipDispatch records the run as skipped because of provided reason and
+// finishes its handle. It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) skipDispatch(item dispatch, reason error) {
+	item.handle.finish(scheduler.skipRun(item.task, runPlan{scheduled: item.scheduled, triggered: item.triggered}, reason))
+}
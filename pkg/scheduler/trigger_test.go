@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestScheduler_TriggerTask tests that Scheduler.TriggerTask runs the task
+// immediately with its own parameters and the handle returns the result.
+func TestScheduler_TriggerTask(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Report", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task, month string) string {
+		return "report for " + month
+	}, "January")
+
+	handle, err := newScheduler.TriggerTaskByName("Report", TriggerWithParameters("March"))
+	if err != nil {
+		t.Fatalf("Task has not been triggered. Error: %v.", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	run, err := handle.Wait(ctx)
+	if err != nil {
+		t.Fatalf("Triggered run has not finished. Error: %v.", err)
+	}
+	if run.Status != RunSucceeded || !run.Triggered || run.Result != "report for March" {
+		t.Fatalf("Incorrect triggered run: %+v.", run)
+	}
+	if last, _ := task.LastRun(); !last.Triggered {
+		t.Fatalf("Triggered run has not been recorded: %+v.", last)
+	}
+
+	newScheduler.mutex.Lock()
+	nextFire := task.nextFire
+	newScheduler.mutex.Unlock()
+	if !nextFire.Equal(start) {
+		t.Fatalf("Schedule of the task has been changed: %s.", nextFire)
+	}
+
+	if _, err = newScheduler.TriggerTaskByID("unknown"); err == nil {
+		t.Fatalf("Unknown task has been triggered.")
+	}
+}
+
+// TestScheduler_TriggerTask_Overlap tests that triggered run is skipped while
+// the previous run of the task with OverlapSkip is in progress.
+func TestScheduler_TriggerTask_Overlap(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	task := newScheduler.ScheduleTask("Task", nil, nil, time.Hour, func(task *Task) {
+		<-release
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	handle, _ := newScheduler.TriggerTask(task)
+	run, ok := handle.Result()
+	close(release)
+
+	if !ok || run.Status != RunSkipped || !errors.Is(run.Err, ErrTaskRunning) {
+		t.Fatalf("Overlapping triggered run has not been skipped: %+v.", run)
+	}
+}
+
+// TestScheduler_TriggerTask_ResetTimer tests that the next fire time is
+// calculated from the trigger time with TriggerResetTimer.
+func TestScheduler_TriggerTask_ResetTimer(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(30 * time.Minute)
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+
+	triggered := time.Now()
+	handle, _ := newScheduler.TriggerTask(task, TriggerResetTimer())
+	<-handle.Done()
+
+	newScheduler.mutex.Lock()
+	nextFire := task.nextFire
+	newScheduler.mutex.Unlock()
+	if nextFire.Before(triggered.Add(time.Hour)) || nextFire.After(time.Now().Add(time.Hour)) {
+		t.Fatalf("Timer has not been reset: %s.", nextFire)
+	}
+}
+
+// TestScheduler_TriggerTask_Saturated tests that triggered run is skipped when
+// all workers are busy and the queue is full with SaturationDrop.
+func TestScheduler_TriggerTask_Saturated(t *testing.T) {
+	newScheduler := New(WithWorkers(1), WithQueueSize(0), WithSaturationPolicy(SaturationDrop, 0))
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	release := make(chan struct{})
+	blocking := NewTask("", "Blocking", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(blocking, func(task *Task) {
+		<-release
+	})
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+
+	running, _ := newScheduler.TriggerTask(blocking)
+	handle, _ := newScheduler.TriggerTask(task)
+	run, ok := handle.Result()
+	close(release)
+	<-running.Done()
+
+	if !ok || run.Status != RunSkipped || !run.Triggered || !errors.Is(run.Err, ErrSchedulerSaturated) {
+		t.Fatalf("Triggered run has not been skipped on saturation: %+v.", run)
+	}
+}
+
+// TestScheduler_TriggerTask_RateLimited tests that triggered run exceeding the
+// rate limit of the task is skipped.
+func TestScheduler_TriggerTask_RateLimited(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	task.RateLimiter = NewRateLimiter(1, time.Hour)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+
+	first, _ := newScheduler.TriggerTask(task)
+	<-first.Done()
+	second, _ := newScheduler.TriggerTask(task)
+	run, ok := second.Result()
+
+	if first.run.Status != RunSucceeded {
+		t.Fatalf("Triggered run within the rate limit has not been executed: %+v.", first.run)
+	}
+	if !ok || run.Status != RunSkipped || !errors.Is(run.Err, ErrRateLimited) {
+		t.Fatalf("Triggered run exceeding the rate limit has not been skipped: %+v.", run)
+	}
+}
+
+// TestScheduler_TriggerTask_FromJob tests that the job triggering another task
+// on the saturated scheduler with SaturationBlock is not blocked and the
+// triggered run is executed after it.
+func TestScheduler_TriggerTask_FromJob(t *testing.T) {
+	newScheduler := New(WithWorkers(1), WithQueueSize(0))
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+	handles := make(chan *RunHandle, 1)
+	source := NewTask("", "Source", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(source, func(source *Task) {
+		handle, _ := newScheduler.TriggerTask(task)
+		handles <- handle
+	})
+
+	_, _ = newScheduler.TriggerTask(source)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var handle *RunHandle
+	select {
+	case handle = <-handles:
+	case <-ctx.Done():
+		t.Fatalf("Job triggering the task on the saturated scheduler has been blocked.")
+	}
+	run, err := handle.Wait(ctx)
+	if err != nil || run.Status != RunSucceeded || !run.Triggered {
+		t.Fatalf("Run triggered from the job has not been executed: %+v, %v.", run, err)
+	}
+}
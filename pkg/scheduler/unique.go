@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateName is matched by DuplicateNameError using errors.Is.
+var ErrDuplicateName = errors.New("task with the same name is already scheduled")
+
+// DuplicateNameError is returned when the task is scheduled with the name of
+// already scheduled task and DuplicateReject policy is used.
+type DuplicateNameError struct {
+	// Name is the duplicated name.
+	Name string
+	// Existing is the already scheduled task with the same name.
+	Existing *Task
+}
+
+// Error returns description of the error.
+func (err *DuplicateNameError) Error() string {
+	return fmt.Sprintf("task with name: %s is already scheduled with id: %s", err.Name, err.Existing.ID)
+}
+
+// Unwrap returns ErrDuplicateName.
+func (err *DuplicateNameError) Unwrap() error {
+	return ErrDuplicateName
+}
+
+// DuplicatePolicy defines what happens when the task is scheduled with the name
+// of already scheduled task.
+type DuplicatePolicy int
+
+const (
+	// DuplicateAllow schedules both tasks, names are not unique. It is the
+	// default policy.
+	DuplicateAllow DuplicatePolicy = iota
+	// DuplicateReject returns DuplicateNameError and keeps the existing task.
+	DuplicateReject
+	// DuplicateReplace stops the existing task and schedules the new one
+	// instead, the context of the new task is kept as is.
+	DuplicateReplace
+	// DuplicateReplaceKeepContext works like DuplicateReplace, but the context
+	// of the existing task is copied to the new task.
+	DuplicateReplaceKeepContext
+	// DuplicateIgnore keeps the existing task and doesn't schedule the new one,
+	// no error is returned.
+	DuplicateIgnore
+)
+
+// WithUniqueNames makes the Scheduler enforce unique task names using provided
+// policy, so repeated initialization code doesn't create duplicated tasks.
+func WithUniqueNames(policy DuplicatePolicy) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.duplicates = policy
+	}
+}
+
+// resolveDuplicate applies the duplicate policy to the task that is being
+// scheduled. It returns the existing task if the new task shall not be
+// scheduled. It shall be called with the scheduler mutex locked.
+func (scheduler *Scheduler) resolveDuplicate(task *Task) (*Task, error) {
+	if scheduler.duplicates == DuplicateAllow {
+		return nil, nil
+	}
+	existing := firstIndexed(scheduler.tasksIndex().byName, task.Name)
+	if existing == nil || existing == task {
+		return nil, nil
+	}
+
+	switch scheduler.duplicates {
+	case DuplicateReject:
+		return existing, &DuplicateNameError{Name: task.Name, Existing: existing}
+	case DuplicateIgnore:
+		return existing, nil
+	case DuplicateReplaceKeepContext:
+		existing.mutex.RLock()
+		context := make(map[string]interface{}, len(existing.context))
+		for key, value := range existing.context {
+			context[key] = value
+		}
+		existing.mutex.RUnlock()
+		task.mutex.Lock()
+		task.context = context
+		task.mutex.Unlock()
+	}
+
+	if existing.cancel != nil {
+		existing.cancel()
+	}
+	existing.closeStopSignal()
+	if scheduler.removeTask(existing) == nil {
+		scheduler.emit(EventTaskRemoved, existing, nil)
+	}
+	return nil, nil
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestScheduler_UniqueNames_Reject tests that task with duplicated name is
+// rejected with DuplicateNameError.
+func TestScheduler_UniqueNames_Reject(t *testing.T) {
+	newScheduler := New(WithUniqueNames(DuplicateReject))
+	defer newScheduler.Shutdown(context.Background())
+
+	existing := newScheduler.ScheduleTask("Report", nil, nil, time.Hour, func(task *Task) {})
+	err := newScheduler.Schedule(NewSimpleTask("Report", time.Hour), func(task *Task) {})
+
+	var duplicate *DuplicateNameError
+	if !errors.As(err, &duplicate) || !errors.Is(err, ErrDuplicateName) || duplicate.Existing != existing {
+		t.Fatalf("Duplicated task has not been rejected. Error: %v.", err)
+	}
+	if len(newScheduler.Tasks) != 1 {
+		t.Fatalf("Incorrect number of tasks. Expected: 1. Actual: %d.", len(newScheduler.Tasks))
+	}
+	if newScheduler.ScheduleTask("Report", nil, nil, time.Hour, func(task *Task) {}) != nil {
+		t.Fatalf("ScheduleTask has returned duplicated task.")
+	}
+}
+
+// TestScheduler_UniqueNames_Ignore tests that task with duplicated name is
+// ignored and the existing task is kept.
+func TestScheduler_UniqueNames_Ignore(t *testing.T) {
+	newScheduler := New(WithUniqueNames(DuplicateIgnore))
+	defer newScheduler.Shutdown(context.Background())
+
+	existing := newScheduler.ScheduleTask("Report", nil, nil, time.Hour, func(task *Task) {})
+	if task := newScheduler.ScheduleTask("Report", nil, nil, time.Hour, func(task *Task) {}); task != existing {
+		t.Fatalf("ScheduleTask has not returned the existing task: %v.", task)
+	}
+
+	ignored := NewSimpleTask("Report", time.Hour)
+	if err := newScheduler.Schedule(ignored, func(task *Task) {}); err != nil {
+		t.Fatalf("Duplicated task has not been ignored. Error: %v.", err)
+	}
+	if len(newScheduler.Tasks) != 1 || newScheduler.FindTaskByName("Report") != existing {
+		t.Fatalf("Existing task has not been kept: %v.", newScheduler.Tasks)
+	}
+}
+
+// TestScheduler_UniqueNames_Replace tests that task with duplicated name
+// replaces the existing task, which is stopped, with or without its context.
+func TestScheduler_UniqueNames_Replace(t *testing.T) {
+	for _, policy := range []DuplicatePolicy{DuplicateReplace, DuplicateReplaceKeepContext} {
+		newScheduler := New(WithUniqueNames(policy))
+
+		existing := newScheduler.ScheduleTask("Report", nil, nil, time.Hour, func(task *Task) {})
+		existing.SetToContext("offset", 10)
+
+		replacement := NewSimpleTask("Report", time.Hour)
+		if err := newScheduler.Schedule(replacement, func(task *Task) {}); err != nil {
+			t.Fatalf("Task has not been replaced. Error: %v.", err)
+		}
+		existing.Wait()
+
+		if len(newScheduler.Tasks) != 1 || newScheduler.FindTaskByName("Report") != replacement {
+			t.Fatalf("Task has not been replaced: %v.", newScheduler.Tasks)
+		}
+
+		offset := replacement.GetFromContext("offset")
+		if policy == DuplicateReplace && offset != nil {
+			t.Fatalf("Context has been carried over: %v.", offset)
+		}
+		if policy == DuplicateReplaceKeepContext && offset != 10 {
+			t.Fatalf("Context has not been carried over: %v.", offset)
+		}
+		_ = newScheduler.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// scheduleUpdate stores the schedule of the task while it is changed by
+// TaskUpdate functions.
+type scheduleUpdate struct {
+	start        time.Time
+	startChanged bool
+	end          time.Time
+	interval     time.Duration
+	cron         string
+	jitter       time.Duration
+	calendar     Calendar
+	policy       CalendarPolicy
+	location     *time.Location
+}
+
+// TaskUpdate changes the schedule of the task, it is applied using
+// Scheduler.UpdateTask.
+type TaskUpdate func(update *scheduleUpdate)
+
+// UpdateInterval makes the task fire every interval, the cron expression of the
+// task is removed. Task with non-positive interval is fired only once.
+func UpdateInterval(interval time.Duration) TaskUpdate {
+	return func(update *scheduleUpdate) {
+		update.interval = interval
+		update.cron = ""
+	}
+}
+
+// UpdateCron makes the task fire at the times matching the cron expression,
+// empty expression switches the task back to the Interval.
+func UpdateCron(spec string) TaskUpdate {
+	return func(update *scheduleUpdate) {
+		update.cron = spec
+	}
+}
+
+// UpdateStart changes the start time of the task, the end time is kept. The task
+// is fired again from the new start time.
+func UpdateStart(start time.Time) TaskUpdate {
+	return func(update *scheduleUpdate) {
+		update.start = start
+		update.startChanged = true
+	}
+}
+
+// UpdateEnd changes the end time of the task, nil removes it, so the task runs
+// until it is stopped.
+func UpdateEnd(end *time.Time) TaskUpdate {
+	return func(update *scheduleUpdate) {
+		update.end = time.Time{}
+		if end != nil {
+			update.end = *end
+		}
+	}
+}
+
+// UpdateJitter changes the maximum delay added to every fire time.
+func UpdateJitter(jitter time.Duration) TaskUpdate {
+	return func(update *scheduleUpdate) {
+		update.jitter = jitter
+	}
+}
+
+// UpdateCalendar changes the calendar of the task and the policy applied to
+// fire times that are not contained in it, nil calendar removes it.
+func UpdateCalendar(calendar Calendar, policy CalendarPolicy) TaskUpdate {
+	return func(update *scheduleUpdate) {
+		update.calendar = calendar
+		update.policy = policy
+	}
+}
+
+// UpdateLocation changes the time zone where the schedule of the task is
+// evaluated, nil uses the location of the start time.
+func UpdateLocation(location *time.Location) TaskUpdate {
+	return func(update *scheduleUpdate) {
+		update.location = location
+	}
+}
+
+// UpdateTask atomically changes the schedule of the scheduled task, its ID,
+// context and history are preserved. The changes take effect from the next
+// fire time, which is calculated from the last fire time of the task using the
+// new schedule, missed fire times are skipped. If the start time is changed,
+// the task is fired again from the new start time. The running and queued runs
+// are not affected. It returns an error if the task is not scheduled or the
+// new schedule is not valid, in this case the task is not changed.
+func (scheduler *Scheduler) UpdateTask(task *Task, updates ...TaskUpdate) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if task.scheduler != scheduler {
+		return fmt.Errorf("task with id: %s cannot be updated, because it was not found", task.ID)
+	}
+
+	update := &scheduleUpdate{
+		start:    *task.Start,
+		end:      task.end,
+		interval: task.Interval,
+		cron:     task.Cron,
+		jitter:   task.Jitter,
+		calendar: task.Calendar,
+		policy:   task.CalendarPolicy,
+		location: task.Location,
+	}
+	for _, apply := range updates {
+		apply(update)
+	}
+
+	var cron *CronSchedule
+	if update.cron != "" {
+		var err error
+		if cron, err = ParseCron(update.cron); err != nil {
+			return fmt.Errorf("task with id: %s cannot be updated: %w", task.ID, err)
+		}
+	}
+	if !update.end.IsZero() && !update.end.After(update.start) {
+		return fmt.Errorf("task with id: %s cannot be updated, because end %s is not after start %s", task.ID, update.end, update.start)
+	}
+
+	// The task mutex guards the schedule read by Task.NextRuns.
+	task.mutex.Lock()
+	start := update.start
+	task.Start = &start
+	task.end = update.end
+	task.Duration = nil
+	if !update.end.IsZero() {
+		duration := update.end.Sub(update.start)
+		task.Duration = &duration
+	}
+	task.Interval = update.interval
+	task.Cron = update.cron
+	task.cron = cron
+	task.Jitter = update.jitter
+	task.Calendar = update.calendar
+	task.CalendarPolicy = update.policy
+	task.Location = update.location
+	task.mutex.Unlock()
+
+	now := time.Now()
+	switch {
+	case update.startChanged || task.last.IsZero():
+		task.planned = task.firstFireTime(start)
+		if !task.last.IsZero() && task.planned.Before(now) && !task.once() {
+			task.planned = task.nextPlanned(task.planned, now)
+		}
+	default:
+		task.planned = task.nextPlanned(task.last, now)
+	}
+
+	if !task.paused.Load() {
+		scheduler.removeTimer(task)
+		scheduler.reschedule(task)
+	}
+	return nil
+}
+
+// parseCron parses the Cron expression of the task.
+func (task *Task) parseCron() error {
+	task.cron = nil
+	if task.Cron == "" {
+		return nil
+	}
+	cron, err := ParseCron(task.Cron)
+	if err != nil {
+		return fmt.Errorf("task with id: %s cannot be scheduled: %w", task.ID, err)
+	}
+	task.cron = cron
+	return nil
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestScheduler_UpdateTask tests that Scheduler.UpdateTask changes the interval
+// of the running task while its ID, context and history are preserved.
+func TestScheduler_UpdateTask(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	task := newScheduler.ScheduleTask("Task", nil, nil, time.Hour, func(task *Task) {})
+	task.SetToContext("key", "value")
+	id := task.ID
+	time.Sleep(50 * time.Millisecond)
+
+	if err := newScheduler.UpdateTask(task, UpdateInterval(20*time.Millisecond)); err != nil {
+		t.Fatalf("Task has not been updated. Error: %v.", err)
+	}
+	time.Sleep(110 * time.Millisecond)
+
+	runs := CountRuns(task, RunSucceeded)
+	if runs < 4 || runs > 7 {
+		t.Fatalf("Incorrect number of runs after update: %d.", runs)
+	}
+	if task.ID != id || task.GetFromContext("key") != "value" || task.History()[0].Number != 1 {
+		t.Fatalf("Task has not been preserved: %s, %v, %v.", task.ID, task.GetFromContext("key"), task.History())
+	}
+	if newScheduler.FindTaskByID(id) != task {
+		t.Fatalf("Updated task has not been found.")
+	}
+}
+
+// TestScheduler_UpdateTask_Cron tests that Scheduler.UpdateTask switches the
+// task to the cron expression and applies the jitter from the next fire time.
+func TestScheduler_UpdateTask_Cron(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Task", &start, nil, time.Minute, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+
+	if err := newScheduler.UpdateTask(task, UpdateCron("0 0 1 1 *"), UpdateJitter(time.Minute)); err != nil {
+		t.Fatalf("Task has not been updated. Error: %v.", err)
+	}
+
+	newScheduler.mutex.Lock()
+	planned, nextFire := task.planned, task.nextFire
+	newScheduler.mutex.Unlock()
+
+	expected := time.Date(start.Year()+1, 1, 1, 0, 0, 0, 0, start.Location())
+	if !planned.Equal(expected) {
+		t.Fatalf("Incorrect planned time. Expected: %s. Actual: %s.", expected, planned)
+	}
+	if nextFire.Before(planned) || !nextFire.Before(planned.Add(time.Minute)) || task.Cron != "0 0 1 1 *" {
+		t.Fatalf("Jitter has not been applied: %s.", nextFire)
+	}
+}
+
+// TestScheduler_UpdateTask_Invalid tests that invalid update is rejected and the
+// task is not changed.
+func TestScheduler_UpdateTask_Invalid(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	task := newScheduler.ScheduleTask("Task", nil, nil, time.Hour, func(task *Task) {})
+	past := task.Start.Add(-time.Hour)
+
+	if err := newScheduler.UpdateTask(task, UpdateInterval(time.Minute), UpdateCron("invalid")); err == nil {
+		t.Fatalf("Invalid cron expression has been accepted.")
+	}
+	if err := newScheduler.UpdateTask(task, UpdateEnd(&past)); err == nil {
+		t.Fatalf("End before start has been accepted.")
+	}
+	if task.Interval != time.Hour || task.Cron != "" || task.Duration != nil {
+		t.Fatalf("Task has been changed by invalid update: %s.", task)
+	}
+
+	if err := newScheduler.UpdateTask(NewSimpleTask("Other", time.Hour)); err == nil {
+		t.Fatalf("Not scheduled task has been updated.")
+	}
+}
+
+// TestScheduler_UpdateTask_End tests that the task is completed once its
+// updated end time is reached.
+func TestScheduler_UpdateTask_End(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	task := newScheduler.ScheduleTask("Task", nil, nil, 10*time.Millisecond, func(task *Task) {})
+	end := time.Now().Add(50 * time.Millisecond)
+	if err := newScheduler.UpdateTask(task, UpdateEnd(&end)); err != nil {
+		t.Fatalf("Task has not been updated. Error: %v.", err)
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		task.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatalf("Task has not been completed at the updated end time.")
+	}
+}
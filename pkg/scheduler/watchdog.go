@@ -0,0 +1,284 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultWatchdogInterval is how often the Watchdog checks tasks, if the
+// tolerance is not positive.
+const DefaultWatchdogInterval = time.Second
+
+// DefaultWebhookTimeout limits how long WebhookNotifier waits for the response,
+// if its Client is not set.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// webhookClient is used by WebhookNotifier without Client.
+var webhookClient = &http.Client{Timeout: DefaultWebhookTimeout}
+
+// Alert is sent by the Watchdog when the task has missed its heartbeat, or when
+// the heartbeat has been received again.
+type Alert struct {
+	// Task is the task that has missed its heartbeat.
+	Task *Task `json:"-"`
+	// TaskID is the ID of the task.
+	TaskID string `json:"task_id"`
+	// TaskName is the name of the task.
+	TaskName string `json:"task_name"`
+	// Schedule describes the schedule of the task in plain English.
+	Schedule string `json:"schedule"`
+	// LastHeartbeat is when the last run of the task has started, it is zero if
+	// the task has not been executed yet.
+	LastHeartbeat time.Time `json:"last_heartbeat,omitempty"`
+	// Expected is when the task has been expected to run.
+	Expected time.Time `json:"expected"`
+	// Overdue is how long the run is overdue.
+	Overdue time.Duration `json:"overdue"`
+	// Resolved is set when the task has run again after the alert.
+	Resolved bool `json:"resolved,omitempty"`
+	// Time is when the alert has been created.
+	Time time.Time `json:"time"`
+}
+
+// Notifier delivers alerts of the Watchdog.
+type Notifier interface {
+	// Notify delivers the alert.
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NotifierFunc is a callback that implements Notifier.
+type NotifierFunc func(ctx context.Context, alert Alert) error
+
+// Notify calls the callback.
+func (function NotifierFunc) Notify(ctx context.Context, alert Alert) error {
+	return function(ctx, alert)
+}
+
+// WebhookNotifier posts alerts as JSON to the URL.
+type WebhookNotifier struct {
+	// URL is the webhook target.
+	URL string
+	// Header stores additional request headers.
+	Header http.Header
+	// Client is used to send the request, the client with
+	// DefaultWebhookTimeout is used if it is nil.
+	Client *http.Client
+}
+
+// NewWebhookNotifier creates a new WebhookNotifier with provided URL and the
+// client with DefaultWebhookTimeout.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: DefaultWebhookTimeout}}
+}
+
+// Notify posts the alert, response with non-2xx status is returned as an
+// error.
+func (notifier *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range notifier.Header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	client := notifier.Client
+	if client == nil {
+		client = webhookClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded with unexpected status %d", notifier.URL, response.StatusCode)
+	}
+	return nil
+}
+
+// Watchdog is a dead man's switch that alerts when the scheduled task has not
+// run within its expected cadence, derived from the Interval or the Cron of the
+// task, plus the tolerance. Paused tasks are checked as well. The alert is sent
+// once per missed heartbeat and resolved when the task runs again.
+type Watchdog struct {
+	scheduler *Scheduler
+	notifier  Notifier
+	tolerance time.Duration
+	// timeout limits every notification, it is the check interval.
+	timeout time.Duration
+	// seen stores when the task without runs has been seen for the first time.
+	seen map[*Task]time.Time
+	// alerts stores unresolved alerts by task.
+	alerts map[*Task]Alert
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+	mutex  sync.Mutex
+}
+
+// Watch starts the Watchdog that checks tasks of the Scheduler every half of
+// the tolerance and sends alerts to the notifier. Every notification is
+// cancelled after the check interval, so the hanging notifier doesn't block
+// later checks.
+func (scheduler *Scheduler) Watch(notifier Notifier, tolerance time.Duration) *Watchdog {
+	interval := tolerance / 2
+	if interval <= 0 {
+		interval = DefaultWatchdogInterval
+	}
+	watchdog := &Watchdog{
+		scheduler: scheduler,
+		notifier:  notifier,
+		tolerance: tolerance,
+		timeout:   interval,
+		seen:      make(map[*Task]time.Time),
+		alerts:    make(map[*Task]Alert),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go watchdog.loop(interval)
+	return watchdog
+}
+
+// Stop stops checking tasks and waits until the current check is finished.
+func (watchdog *Watchdog) Stop() {
+	watchdog.once.Do(func() {
+		close(watchdog.stop)
+	})
+	<-watchdog.done
+}
+
+// loop checks tasks periodically until the Watchdog is stopped.
+func (watchdog *Watchdog) loop(interval time.Duration) {
+	defer close(watchdog.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			watchdog.Check(now)
+		case <-watchdog.stop:
+			return
+		}
+	}
+}
+
+// Check checks all tasks at provided time, sends new and resolved alerts to
+// the notifier and returns them. Errors of the notifier are logged to the
+// logger of the Scheduler, or to slog.Default if it is not set.
+func (watchdog *Watchdog) Check(now time.Time) []Alert {
+	watchdog.mutex.Lock()
+	defer watchdog.mutex.Unlock()
+
+	alerts := watchdog.collect(now)
+	for index := range alerts {
+		// The schedule is described after the scheduler mutex is released.
+		alerts[index].Schedule = alerts[index].Task.Describe()
+	}
+	for _, alert := range alerts {
+		if err := watchdog.notify(alert); err != nil {
+			logger := watchdog.scheduler.logger
+			if logger == nil {
+				logger = slog.Default()
+			}
+			logger.Error("watchdog alert not delivered", "task_id", alert.TaskID, "task_name", alert.TaskName, "error", err.Error())
+		}
+	}
+	return alerts
+}
+
+// notify sends the alert to the notifier within the timeout of the Watchdog.
+func (watchdog *Watchdog) notify(alert Alert) error {
+	ctx, cancel := context.WithTimeout(context.Background(), watchdog.timeout)
+	defer cancel()
+	return watchdog.notifier.Notify(ctx, alert)
+}
+
+// collect returns new and resolved alerts. It shall be called with the
+// watchdog mutex locked.
+func (watchdog *Watchdog) collect(now time.Time) []Alert {
+	scheduler := watchdog.scheduler
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	alerts := make([]Alert, 0)
+	scheduled := make(map[*Task]bool, len(scheduler.Tasks))
+	for _, task := range scheduler.Tasks {
+		scheduled[task] = true
+		heartbeat := task.heartbeat()
+		if _, ok := watchdog.seen[task]; !ok {
+			watchdog.seen[task] = now
+		}
+
+		alert, alerted := watchdog.alerts[task]
+		if alerted && heartbeat.After(alert.LastHeartbeat) {
+			delete(watchdog.alerts, task)
+			alert.LastHeartbeat, alert.Resolved, alert.Time = heartbeat, true, now
+			alerts = append(alerts, alert)
+			alerted = false
+		}
+		if alerted {
+			continue
+		}
+
+		expected := task.expectedRun(heartbeat, watchdog.seen[task])
+		if expected.IsZero() || (!task.end.IsZero() && !expected.Before(task.end)) {
+			continue
+		}
+		if overdue := now.Sub(expected); overdue > task.Jitter+watchdog.tolerance {
+			alert = Alert{Task: task, TaskID: task.ID, TaskName: task.Name, LastHeartbeat: heartbeat, Expected: expected, Overdue: overdue, Time: now}
+			watchdog.alerts[task] = alert
+			alerts = append(alerts, alert)
+		}
+	}
+
+	for task := range watchdog.seen {
+		if !scheduled[task] {
+			delete(watchdog.seen, task)
+			delete(watchdog.alerts, task)
+		}
+	}
+	return alerts
+}
+
+// heartbeat returns when the latest executed run of the task has started,
+// skipped runs are ignored. It returns zero time if no run has been executed.
+func (task *Task) heartbeat() time.Time {
+	task.mutex.RLock()
+	defer task.mutex.RUnlock()
+	for index := len(task.history) - 1; index >= 0; index-- {
+		if run := task.history[index]; run.Status != RunSkipped {
+			return run.Started
+		}
+	}
+	return time.Time{}
+}
+
+// expectedRun returns when the task is expected to run after the heartbeat,
+// the task without heartbeat is expected at its first fire time, or one
+// cadence after it has been seen by the watchdog if that time has passed
+// before. It returns zero time if no run is expected. It shall be called with
+// the scheduler mutex locked.
+func (task *Task) expectedRun(heartbeat time.Time, seen time.Time) time.Time {
+	if heartbeat.IsZero() {
+		first := task.firstFireTime(*task.Start)
+		if first.IsZero() || !first.Before(seen) || task.once() {
+			return task.calendarTime(first)
+		}
+		heartbeat = seen
+	}
+	return task.calendarTime(task.nextPlanned(heartbeat, time.Time{}))
+}
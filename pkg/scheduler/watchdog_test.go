@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWatchdog_Check tests that the alert is sent once when the task misses its
+// heartbeat and it is resolved when the task runs again.
+func TestWatchdog_Check(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	alerts := make([]Alert, 0)
+	watchdog := newScheduler.Watch(NotifierFunc(func(ctx context.Context, alert Alert) error {
+		alerts = append(alerts, alert)
+		return nil
+	}), 2*time.Hour)
+	defer watchdog.Stop()
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+
+	if result := watchdog.Check(time.Now()); len(result) != 0 {
+		t.Fatalf("Alert has been sent before the first fire time: %+v.", result)
+	}
+	late := start.Add(3 * time.Hour)
+	result := watchdog.Check(late)
+	if len(result) != 1 || result[0].TaskID != task.ID || !result[0].Expected.Equal(start) || result[0].Overdue != 3*time.Hour || result[0].Resolved || result[0].Schedule != task.Describe() {
+		t.Fatalf("Incorrect alert of the missed heartbeat: %+v.", result)
+	}
+	if result = watchdog.Check(late.Add(time.Hour)); len(result) != 0 {
+		t.Fatalf("Alert has been sent again: %+v.", result)
+	}
+
+	handle, _ := newScheduler.TriggerTask(task)
+	run, _ := handle.Wait(context.Background())
+	result = watchdog.Check(time.Now())
+	if len(result) != 1 || !result[0].Resolved || !result[0].LastHeartbeat.Equal(run.Started) {
+		t.Fatalf("Alert has not been resolved: %+v.", result)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("Incorrect notified alerts: %+v.", alerts)
+	}
+}
+
+// TestScheduler_Watch tests that the watchdog alerts about the paused task.
+func TestScheduler_Watch(t *testing.T) {
+	newScheduler := New()
+	defer newScheduler.Shutdown(context.Background())
+
+	task := newScheduler.ScheduleTask("Task", nil, nil, 10*time.Millisecond, func(task *Task) {})
+	_ = newScheduler.PauseTask(task)
+
+	alerts := make(chan Alert, 1)
+	watchdog := newScheduler.Watch(NotifierFunc(func(ctx context.Context, alert Alert) error {
+		select {
+		case alerts <- alert:
+		default:
+		}
+		return nil
+	}), 20*time.Millisecond)
+	defer watchdog.Stop()
+
+	select {
+	case alert := <-alerts:
+		if alert.Task != task || alert.TaskName != "Task" {
+			t.Fatalf("Incorrect alert: %+v.", alert)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Paused task has not been reported.")
+	}
+}
+
+// TestWatchdog_Check_Timeout tests that the hanging webhook doesn't block the
+// check and the delivery error is logged.
+func TestWatchdog_Check_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		select {
+		case <-release:
+		case <-request.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	output := &bytes.Buffer{}
+	newScheduler := New(WithLogger(slog.New(slog.NewTextHandler(output, nil))))
+	defer newScheduler.Shutdown(context.Background())
+	watchdog := newScheduler.Watch(NewWebhookNotifier(server.URL), time.Hour)
+	watchdog.Stop()
+	watchdog.timeout = 50 * time.Millisecond
+
+	start := time.Now().Add(time.Hour)
+	task := NewTask("", "Task", &start, nil, time.Hour, nil, nil)
+	_ = newScheduler.Schedule(task, func(task *Task) {})
+	_ = watchdog.Check(time.Now())
+
+	checked := time.Now()
+	if result := watchdog.Check(start.Add(3 * time.Hour)); len(result) != 1 {
+		t.Fatalf("Alert has not been created: %+v.", result)
+	}
+	if elapsed := time.Since(checked); elapsed > time.Second {
+		t.Fatalf("Check has been blocked by the webhook for %s.", elapsed)
+	}
+	if !strings.Contains(output.String(), "watchdog alert not delivered") {
+		t.Fatalf("Delivery error has not been logged: %s.", output.String())
+	}
+}
+
+// TestTask_ExpectedRun tests the expected run time derived from the interval
+// and the cron expression.
+func TestTask_ExpectedRun(t *testing.T) {
+	heartbeat := time.Date(2024, time.March, 1, 10, 15, 0, 0, time.UTC)
+
+	task := &Task{Interval: time.Hour}
+	if expected := task.expectedRun(heartbeat, heartbeat); !expected.Equal(heartbeat.Add(time.Hour)) {
+		t.Fatalf("Incorrect expected run of the interval: %s.", expected)
+	}
+
+	task = &Task{Cron: "0 * * * *"}
+	_ = task.parseCron()
+	if expected := task.expectedRun(heartbeat, heartbeat); !expected.Equal(time.Date(2024, time.March, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Incorrect expected run of the cron expression: %s.", expected)
+	}
+
+	start := heartbeat.Add(-24 * time.Hour)
+	task.Start = &start
+	if expected := task.expectedRun(time.Time{}, heartbeat); !expected.Equal(time.Date(2024, time.March, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Incorrect expected run of the task without heartbeat: %s.", expected)
+	}
+}
+
+// TestWebhookNotifier tests that the alert is posted as JSON and unexpected
+// status is returned as an error.
+func TestWebhookNotifier(t *testing.T) {
+	received := make(chan Alert, 1)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		alert := Alert{}
+		if request.Method != http.MethodPost || request.Header.Get("Authorization") != "token" || json.NewDecoder(request.Body).Decode(&alert) != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- alert
+		writer.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	notifier.Header = http.Header{"Authorization": []string{"token"}}
+	if err := notifier.Notify(context.Background(), Alert{TaskID: "id", TaskName: "Task", Overdue: time.Minute}); err != nil {
+		t.Fatalf("Alert has not been posted. Error: %v.", err)
+	}
+	if alert := <-received; alert.TaskID != "id" || alert.TaskName != "Task" || alert.Overdue != time.Minute {
+		t.Fatalf("Incorrect posted alert: %+v.", alert)
+	}
+
+	status = http.StatusInternalServerError
+	if err := notifier.Notify(context.Background(), Alert{}); err == nil {
+		t.Fatalf("Unexpected status has not been returned as an error.")
+	}
+}
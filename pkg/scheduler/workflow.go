@@ -0,0 +1,353 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrWorkflowCycle is returned when dependency would create a cycle in the
+// Workflow.
+var ErrWorkflowCycle = errors.New("workflow dependency creates a cycle")
+
+// TriggerRule defines when a workflow node is executed, based on the outcome of
+// its upstream nodes.
+type TriggerRule int
+
+const (
+	// AllSucceeded runs the node only if all upstream nodes have succeeded,
+	// otherwise the node is skipped. It is the default rule.
+	AllSucceeded TriggerRule = iota
+	// AllDone runs the node once all upstream nodes have finished, regardless of
+	// their outcome.
+	AllDone
+	// OneFailed runs the node only if at least one upstream node has failed,
+	// otherwise the node is skipped. It is useful for clean-up and alerting.
+	OneFailed
+)
+
+// NodeStatus represents outcome of the workflow node within one workflow run.
+type NodeStatus string
+
+const (
+	// NodePending means that node has not been evaluated yet.
+	NodePending NodeStatus = "pending"
+	// NodeSucceeded means that node function has returned without an error.
+	NodeSucceeded NodeStatus = "succeeded"
+	// NodeFailed means that node function has returned an error.
+	NodeFailed NodeStatus = "failed"
+	// NodeSkipped means that node has not been executed, because its trigger
+	// rule was not satisfied or workflow run has been cancelled, or that its
+	// function has returned an error wrapping ErrSkipped.
+	NodeSkipped NodeStatus = "skipped"
+)
+
+// Workflow is a directed acyclic graph of tasks, where each task is executed
+// after its upstream tasks according to its TriggerRule. Independent tasks are
+// executed in parallel. Workflow.Run could be passed directly to the
+// Scheduler.ScheduleTask to run the whole graph periodically:
+//
+//	workflow := scheduler.NewWorkflow()
+//	extract, _ := workflow.AddTask(scheduler.NewSimpleTask("Extract", 0), extractFunction)
+//	load, _ := workflow.AddTask(scheduler.NewSimpleTask("Load", 0), loadFunction)
+//	_ = load.DependsOn(extract)
+//	newScheduler.ScheduleTask("ETL", nil, nil, time.Hour, workflow.Run)
+//
+// Every node execution is recorded in the history of the node Task, outcome of
+// the whole graph is stored as WorkflowResult in the run record of the
+// scheduled task.
+type Workflow struct {
+	// MaxParallel limits how many nodes are executed at the same time, no limit
+	// if it is not positive.
+	MaxParallel int
+	// nodes stores workflow nodes in the order of their definition.
+	nodes []*WorkflowNode
+	// mutex guards the graph definition.
+	mutex sync.RWMutex
+}
+
+// WorkflowNode is a Task with its function and dependencies within the
+// Workflow.
+type WorkflowNode struct {
+	// Task is executed when the node runs.
+	Task *Task
+	// workflow is the owner of the node.
+	workflow *Workflow
+	// function is called with the Task and parameters.
+	function interface{}
+	// parameters are passed to the function after the Task.
+	parameters []interface{}
+	// upstream stores nodes that shall finish before this node.
+	upstream []*WorkflowNode
+	// rule defines when node is executed.
+	rule TriggerRule
+}
+
+// WorkflowResult stores outcome of the single workflow run.
+type WorkflowResult struct {
+	// Statuses stores status of every node, by the node task ID.
+	Statuses map[string]NodeStatus `json:"statuses"`
+	// Errors stores errors of the failed nodes, by the node task ID.
+	Errors map[string]error `json:"-"`
+}
+
+// Status returns status of the node with provided task.
+func (result *WorkflowResult) Status(task *Task) NodeStatus {
+	if status, ok := result.Statuses[task.ID]; ok {
+		return status
+	}
+	return NodePending
+}
+
+// NewWorkflow creates a new empty Workflow.
+func NewWorkflow() *Workflow {
+	return &Workflow{}
+}
+
+// AddTask adds a new node to the workflow, which calls provided function with
+// the task and parameters, in the same way as Scheduler.ScheduleTask does. It
+// returns an error if the task is already a node of the workflow.
+func (workflow *Workflow) AddTask(task *Task, function interface{}, parameters ...interface{}) (*WorkflowNode, error) {
+	workflow.mutex.Lock()
+	defer workflow.mutex.Unlock()
+
+	if workflow.findNode(task) != nil {
+		return nil, fmt.Errorf("task with id: %s is already added to the workflow", task.ID)
+	}
+
+	node := &WorkflowNode{Task: task, workflow: workflow, function: function, parameters: parameters}
+	workflow.nodes = append(workflow.nodes, node)
+	return node, nil
+}
+
+// findNode returns node of the provided task or nil if task is not in the
+// workflow.
+func (workflow *Workflow) findNode(task *Task) *WorkflowNode {
+	for _, node := range workflow.nodes {
+		if node.Task == task {
+			return node
+		}
+	}
+	return nil
+}
+
+// DependsOn declares that node shall be executed after the provided upstream
+// nodes. It returns ErrWorkflowCycle if any of the dependencies would create a
+// cycle, in this case none of the dependencies is added.
+func (node *WorkflowNode) DependsOn(upstream ...*WorkflowNode) error {
+	node.workflow.mutex.Lock()
+	defer node.workflow.mutex.Unlock()
+
+	for _, upstreamNode := range upstream {
+		if upstreamNode.workflow != node.workflow {
+			return fmt.Errorf("task with id: %s belongs to another workflow", upstreamNode.Task.ID)
+		}
+		if upstreamNode == node || upstreamNode.dependsOn(node) {
+			return fmt.Errorf("%w: %s -> %s", ErrWorkflowCycle, upstreamNode.Task.Name, node.Task.Name)
+		}
+	}
+
+	for _, upstreamNode := range upstream {
+		if !node.dependsOn(upstreamNode) {
+			node.upstream = append(node.upstream, upstreamNode)
+		}
+	}
+	return nil
+}
+
+// dependsOn checks whether provided node is a direct or transitive upstream of
+// this node.
+func (node *WorkflowNode) dependsOn(target *WorkflowNode) bool {
+	visited := make(map[*WorkflowNode]bool)
+	pending := append([]*WorkflowNode(nil), node.upstream...)
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if current == target {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		pending = append(pending, current.upstream...)
+	}
+	return false
+}
+
+// SetTriggerRule changes when the node is executed, AllSucceeded is used by
+// default.
+func (node *WorkflowNode) SetTriggerRule(rule TriggerRule) {
+	node.workflow.mutex.Lock()
+	defer node.workflow.mutex.Unlock()
+	node.rule = rule
+}
+
+// TopologicalOrder returns tasks of the workflow ordered so that every task
+// comes after all its upstream tasks. Independent tasks keep the order of
+// their definition.
+func (workflow *Workflow) TopologicalOrder() []*Task {
+	workflow.mutex.RLock()
+	defer workflow.mutex.RUnlock()
+
+	ordered := make([]*Task, 0, len(workflow.nodes))
+	added := make(map[*WorkflowNode]bool)
+	for len(ordered) < len(workflow.nodes) {
+		for _, node := range workflow.nodes {
+			if added[node] {
+				continue
+			}
+			ready := true
+			for _, upstreamNode := range node.upstream {
+				ready = ready && added[upstreamNode]
+			}
+			if ready {
+				added[node] = true
+				ordered = append(ordered, node.Task)
+			}
+		}
+	}
+	return ordered
+}
+
+// Run executes all nodes of the workflow once and waits for their completion.
+// Nodes are started as soon as their upstream nodes are finished, node is
+// skipped if its trigger rule is not satisfied or provided context is
+// cancelled. Nodes are executed with the middlewares, tracing, events and
+// metrics of the scheduler that owns them or runs the workflow. It returns the outcome of all nodes and an error if any node has
+// failed.
+func (workflow *Workflow) Run(ctx context.Context, task *Task) (*WorkflowResult, error) {
+	workflow.mutex.RLock()
+	nodes := make([]workflowRunNode, len(workflow.nodes))
+	indexes := make(map[*WorkflowNode]int, len(workflow.nodes))
+	for index, node := range workflow.nodes {
+		indexes[node] = index
+		nodes[index] = workflowRunNode{
+			node:     node,
+			upstream: append([]*WorkflowNode(nil), node.upstream...),
+			rule:     node.rule,
+			done:     make(chan struct{}),
+		}
+	}
+	workflow.mutex.RUnlock()
+
+	var slots chan struct{}
+	if workflow.MaxParallel > 0 {
+		slots = make(chan struct{}, workflow.MaxParallel)
+	}
+
+	result := &WorkflowResult{Statuses: make(map[string]NodeStatus), Errors: make(map[string]error)}
+	var resultMutex sync.Mutex
+
+	var group sync.WaitGroup
+	for index := range nodes {
+		group.Add(1)
+		go func(runNode *workflowRunNode) {
+			defer group.Done()
+			defer close(runNode.done)
+
+			succeeded, failed := 0, 0
+			for _, upstreamNode := range runNode.upstream {
+				upstreamRun := &nodes[indexes[upstreamNode]]
+				<-upstreamRun.done
+				switch upstreamRun.status {
+				case NodeSucceeded:
+					succeeded++
+				case NodeFailed:
+					failed++
+				}
+			}
+
+			node := runNode.node
+			runNode.status = NodeSkipped
+			if runNode.rule.satisfied(len(runNode.upstream), succeeded, failed) && acquireSlot(ctx, slots) {
+				if slots != nil {
+					defer func() { <-slots }()
+				}
+				err := node.Task.executeScheduled(ctx, runPlan{scheduler: nodeOwner(ctx, node.Task)}, node.function, node.parameters...)
+				switch {
+				case errors.Is(err, ErrSkipped):
+					runNode.status = NodeSkipped
+				case err != nil:
+					runNode.status, runNode.err = NodeFailed, err
+				default:
+					runNode.status = NodeSucceeded
+				}
+			}
+
+			resultMutex.Lock()
+			result.Statuses[node.Task.ID] = runNode.status
+			if runNode.err != nil {
+				result.Errors[node.Task.ID] = runNode.err
+			}
+			resultMutex.Unlock()
+		}(&nodes[index])
+	}
+	group.Wait()
+
+	var failures []error
+	for _, runNode := range nodes {
+		if runNode.err != nil {
+			failures = append(failures, fmt.Errorf("task %s: %w", runNode.node.Task.Name, runNode.err))
+		}
+	}
+	return result, errors.Join(failures...)
+}
+
+// nodeOwner returns the Scheduler that executes the node: the scheduler of its
+// task or, if the task is not scheduled, the scheduler executing the workflow.
+// It returns nil if the workflow is not run by any scheduler.
+func nodeOwner(ctx context.Context, node *Task) *Scheduler {
+	if scheduler := node.owner(); scheduler != nil {
+		return scheduler
+	}
+	return schedulerFromContext(ctx)
+}
+
+// acquireSlot waits for the free slot of the workflow run, it returns false if
+// the context is cancelled before. Nil slots mean that the number of parallel
+// nodes is not limited.
+func acquireSlot(ctx context.Context, slots chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if slots == nil {
+		return true
+	}
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// workflowRunNode stores state of the node within one workflow run.
+type workflowRunNode struct {
+	// node is the workflow node definition.
+	node *WorkflowNode
+	// upstream is a snapshot of the node dependencies.
+	upstream []*WorkflowNode
+	// rule is a snapshot of the node trigger rule.
+	rule TriggerRule
+	// done is closed once node is finished or skipped.
+	done chan struct{}
+	// status is the outcome of the node, it is set before done is closed.
+	status NodeStatus
+	// err is the error returned by the node function.
+	err error
+}
+
+// satisfied checks whether node with provided number of upstream nodes and
+// their outcome shall be executed.
+func (rule TriggerRule) satisfied(upstream int, succeeded int, failed int) bool {
+	switch rule {
+	case AllDone:
+		return true
+	case OneFailed:
+		return failed > 0
+	default:
+		return succeeded == upstream
+	}
+}
@@ -0,0 +1,283 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// CreateWorkflowNode adds a new node to the workflow that appends its name to
+// the provided log and fails if failure is true.
+func CreateWorkflowNode(t *testing.T, workflow *Workflow, name string, log *[]string, mutex *sync.Mutex, failure bool) *WorkflowNode {
+	node, err := workflow.AddTask(NewSimpleTask(name, 0), func(task *Task) error {
+		mutex.Lock()
+		*log = append(*log, task.Name)
+		mutex.Unlock()
+		if failure {
+			return errors.New(task.Name + " failed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Node %s has not been added: %v.", name, err)
+	}
+	return node
+}
+
+// IndexOf returns index of the value in the list or -1.
+func IndexOf(list []string, value string) int {
+	for index, item := range list {
+		if item == value {
+			return index
+		}
+	}
+	return -1
+}
+
+// TestWorkflow_Run tests that Workflow.Run executes nodes after their upstream
+// nodes and stores statuses in the run record.
+func TestWorkflow_Run(t *testing.T) {
+	var log []string
+	var mutex sync.Mutex
+
+	workflow := NewWorkflow()
+	a := CreateWorkflowNode(t, workflow, "A", &log, &mutex, false)
+	b := CreateWorkflowNode(t, workflow, "B", &log, &mutex, false)
+	c := CreateWorkflowNode(t, workflow, "C", &log, &mutex, false)
+
+	if err := b.DependsOn(a); err != nil {
+		t.Fatalf("Dependency has not been added: %v.", err)
+	}
+	if err := c.DependsOn(a, b); err != nil {
+		t.Fatalf("Dependency has not been added: %v.", err)
+	}
+
+	task := NewSimpleTask("Workflow", time.Second)
+	if err := task.execute(context.Background(), workflow.Run); err != nil {
+		t.Fatalf("Workflow has failed: %v.", err)
+	}
+
+	if IndexOf(log, "A") != 0 || IndexOf(log, "B") != 1 || IndexOf(log, "C") != 2 {
+		t.Fatalf("Nodes have been executed in incorrect order: %v.", log)
+	}
+
+	run, _ := task.LastRun()
+	result := run.Result.(*WorkflowResult)
+	for _, node := range []*WorkflowNode{a, b, c} {
+		if result.Status(node.Task) != NodeSucceeded {
+			t.Fatalf("Node %s has incorrect status: %s.", node.Task.Name, result.Status(node.Task))
+		}
+		if history := node.Task.History(); len(history) != 1 {
+			t.Fatalf("Node %s execution has not been recorded: %v.", node.Task.Name, history)
+		}
+	}
+}
+
+// TestWorkflow_Run_FailurePropagation tests that Workflow.Run skips nodes
+// whose trigger rule is not satisfied after upstream failure.
+func TestWorkflow_Run_FailurePropagation(t *testing.T) {
+	var log []string
+	var mutex sync.Mutex
+
+	workflow := NewWorkflow()
+	extract := CreateWorkflowNode(t, workflow, "Extract", &log, &mutex, true)
+	transform := CreateWorkflowNode(t, workflow, "Transform", &log, &mutex, false)
+	load := CreateWorkflowNode(t, workflow, "Load", &log, &mutex, false)
+	alert := CreateWorkflowNode(t, workflow, "Alert", &log, &mutex, false)
+	cleanup := CreateWorkflowNode(t, workflow, "Cleanup", &log, &mutex, false)
+
+	_ = transform.DependsOn(extract)
+	_ = load.DependsOn(transform)
+	_ = alert.DependsOn(extract, transform)
+	alert.SetTriggerRule(OneFailed)
+	_ = cleanup.DependsOn(load)
+	cleanup.SetTriggerRule(AllDone)
+
+	task := NewSimpleTask("Workflow", time.Second)
+	if err := task.execute(context.Background(), workflow.Run); err == nil {
+		t.Fatalf("Workflow with failed node has not returned an error.")
+	}
+
+	run, _ := task.LastRun()
+	result := run.Result.(*WorkflowResult)
+	expected := map[*WorkflowNode]NodeStatus{
+		extract:   NodeFailed,
+		transform: NodeSkipped,
+		load:      NodeSkipped,
+		alert:     NodeSucceeded,
+		cleanup:   NodeSucceeded,
+	}
+	for node, status := range expected {
+		if result.Status(node.Task) != status {
+			t.Fatalf("Node %s has incorrect status. Expected: %s. Actual: %s.", node.Task.Name, status, result.Status(node.Task))
+		}
+	}
+
+	if result.Errors[extract.Task.ID] == nil {
+		t.Fatalf("Error of the failed node has not been recorded.")
+	}
+}
+
+// TestWorkflow_Run_Parallel tests that Workflow.Run executes independent nodes
+// in parallel and respects Workflow.MaxParallel.
+func TestWorkflow_Run_Parallel(t *testing.T) {
+	for _, testCase := range []struct {
+		maxParallel int
+		expected    int
+	}{{0, 3}, {2, 2}} {
+		var running, maximum int
+		var mutex sync.Mutex
+
+		workflow := NewWorkflow()
+		workflow.MaxParallel = testCase.maxParallel
+		for index := 0; index < 3; index++ {
+			_, _ = workflow.AddTask(NewSimpleTask("Node", 0), func(task *Task) {
+				mutex.Lock()
+				running++
+				if running > maximum {
+					maximum = running
+				}
+				mutex.Unlock()
+				time.Sleep(100 * time.Millisecond)
+				mutex.Lock()
+				running--
+				mutex.Unlock()
+			})
+		}
+
+		if _, err := workflow.Run(context.Background(), nil); err != nil {
+			t.Fatalf("Workflow has failed: %v.", err)
+		}
+
+		if maximum != testCase.expected {
+			t.Fatalf("Incorrect number of parallel nodes with MaxParallel %d. Expected: %d. Actual: %d.", testCase.maxParallel, testCase.expected, maximum)
+		}
+	}
+}
+
+// TestWorkflow_Run_Cancelled tests that nodes waiting for the parallel slot are
+// skipped once the workflow run is cancelled.
+func TestWorkflow_Run_Cancelled(t *testing.T) {
+	started, release := make(chan struct{}, 3), make(chan struct{})
+	workflow := NewWorkflow()
+	workflow.MaxParallel = 1
+	for index := 0; index < 3; index++ {
+		_, _ = workflow.AddTask(NewSimpleTask("Node", 0), func(task *Task) {
+			started <- struct{}{}
+			<-release
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan *WorkflowResult, 1)
+	go func() {
+		result, _ := workflow.Run(ctx, nil)
+		finished <- result
+	}()
+	<-started
+	cancel()
+	// Nodes waiting for the slot shall not wait until it is released.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	result := <-finished
+	counts := make(map[NodeStatus]int)
+	for _, status := range result.Statuses {
+		counts[status]++
+	}
+	if counts[NodeSucceeded] != 1 || counts[NodeSkipped] != 2 || len(started) != 0 {
+		t.Fatalf("Nodes waiting for the slot have not been skipped: %v.", result.Statuses)
+	}
+}
+
+// TestWorkflow_Run_Skipped tests that the node returning ErrSkipped is skipped
+// and doesn't fail the workflow.
+func TestWorkflow_Run_Skipped(t *testing.T) {
+	workflow := NewWorkflow()
+	skipped, _ := workflow.AddTask(NewSimpleTask("Skipped", 0), func(task *Task) error {
+		return fmt.Errorf("nothing to do: %w", ErrSkipped)
+	})
+	onFailure, _ := workflow.AddTask(NewSimpleTask("On Failure", 0), func(task *Task) {})
+	_ = onFailure.DependsOn(skipped)
+	onFailure.SetTriggerRule(OneFailed)
+
+	result, err := workflow.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Skipped node has failed the workflow: %v.", err)
+	}
+	if result.Statuses[skipped.Task.ID] != NodeSkipped || result.Statuses[onFailure.Task.ID] != NodeSkipped || len(result.Errors) != 0 {
+		t.Fatalf("Incorrect statuses of the skipped node: %v, %v.", result.Statuses, result.Errors)
+	}
+}
+
+// TestWorkflow_Run_Middleware tests that nodes of the workflow run by the
+// scheduled task are executed through the middlewares of its scheduler.
+func TestWorkflow_Run_Middleware(t *testing.T) {
+	var names []string
+	var mutex sync.Mutex
+	newScheduler := New(WithMiddleware(func(next Job) Job {
+		return func(ctx context.Context, task *Task) (interface{}, error) {
+			mutex.Lock()
+			names = append(names, task.Name)
+			mutex.Unlock()
+			return next(ctx, task)
+		}
+	}))
+	defer newScheduler.Shutdown(context.Background())
+
+	workflow := NewWorkflow()
+	a, _ := workflow.AddTask(NewSimpleTask("A", 0), func(task *Task) {})
+	b, _ := workflow.AddTask(NewSimpleTask("B", 0), func(task *Task) {})
+	_ = b.DependsOn(a)
+
+	task := NewTask("", "Workflow", nil, nil, 0, nil, nil)
+	_ = newScheduler.Schedule(task, workflow.Run)
+	task.Wait()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(names) != 3 || names[0] != "Workflow" || names[1] != "A" || names[2] != "B" {
+		t.Fatalf("Middleware has not observed the workflow nodes: %v.", names)
+	}
+}
+
+// TestWorkflowNode_DependsOn_Cycle tests that WorkflowNode.DependsOn rejects
+// dependencies that create a cycle.
+func TestWorkflowNode_DependsOn_Cycle(t *testing.T) {
+	workflow := NewWorkflow()
+	a, _ := workflow.AddTask(NewSimpleTask("A", 0), func(task *Task) {})
+	b, _ := workflow.AddTask(NewSimpleTask("B", 0), func(task *Task) {})
+	c, _ := workflow.AddTask(NewSimpleTask("C", 0), func(task *Task) {})
+
+	_ = b.DependsOn(a)
+	_ = c.DependsOn(b)
+
+	for _, dependency := range [][2]*WorkflowNode{{a, c}, {a, a}} {
+		if err := dependency[0].DependsOn(dependency[1]); !errors.Is(err, ErrWorkflowCycle) {
+			t.Fatalf("Cycle %s -> %s has not been detected: %v.", dependency[1].Task.Name, dependency[0].Task.Name, err)
+		}
+	}
+
+	order := workflow.TopologicalOrder()
+	if len(order) != 3 || order[0] != a.Task || order[1] != b.Task || order[2] != c.Task {
+		t.Fatalf("Incorrect topological order: %v.", order)
+	}
+}
+
+// TestWorkflow_AddTask_Duplicate tests that Workflow.AddTask rejects the task
+// that is already in the workflow.
+func TestWorkflow_AddTask_Duplicate(t *testing.T) {
+	workflow := NewWorkflow()
+	task := NewSimpleTask("A", 0)
+
+	if _, err := workflow.AddTask(task, func(task *Task) {}); err != nil {
+		t.Fatalf("Task has not been added: %v.", err)
+	}
+
+	if _, err := workflow.AddTask(task, func(task *Task) {}); err == nil {
+		t.Fatalf("Duplicated task has been added to the workflow.")
+	}
+}